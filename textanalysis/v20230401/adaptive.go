@@ -0,0 +1,163 @@
+package v20230401
+
+import (
+	"context"
+	"math"
+	"sync"
+)
+
+// AdaptiveLimit configures an AdaptiveLimiter.
+type AdaptiveLimit struct {
+	// InitialLimit Number of concurrent requests allowed at start.
+	InitialLimit int
+	// MinLimit Lower bound of the limit.
+	MinLimit int
+	// MaxLimit Upper bound of the limit.
+	MaxLimit int
+	// BackoffRatio Factor applied to the limit on throttling, between 0 and 1.
+	BackoffRatio float64
+	// OnThrottle Called with the updated stats after each throttled response.
+	OnThrottle func(AdaptiveLimiterStats)
+}
+
+// DefaultAdaptiveLimit starts at 4 concurrent requests and adapts between 1 and 64, halving on throttling.
+var DefaultAdaptiveLimit = AdaptiveLimit{
+	InitialLimit: 4,
+	MinLimit:     1,
+	MaxLimit:     64,
+	BackoffRatio: 0.5,
+}
+
+// AdaptiveOutcome is the outcome of a request reported to an AdaptiveLimiter.
+type AdaptiveOutcome int
+
+const (
+	// AdaptiveOutcomeIgnore doesn't affect the limit, e.g. for transport errors.
+	AdaptiveOutcomeIgnore AdaptiveOutcome = iota
+	// AdaptiveOutcomeSuccess grows the limit.
+	AdaptiveOutcomeSuccess
+	// AdaptiveOutcomeThrottled cuts the limit.
+	AdaptiveOutcomeThrottled
+)
+
+// AdaptiveLimiterStats is a snapshot of an AdaptiveLimiter.
+type AdaptiveLimiterStats struct {
+	// Limit Current number of concurrent requests allowed.
+	Limit int
+	// InFlight Number of requests being sent.
+	InFlight int
+	// Waiting Number of requests waiting for a slot.
+	Waiting int
+	// Successes Number of requests that completed without throttling.
+	Successes uint64
+	// Throttles Number of 429 Too Many Requests and 503 Service Unavailable responses.
+	Throttles uint64
+	// Decreases Number of times the limit was cut.
+	Decreases uint64
+}
+
+// AdaptiveLimiter bounds the number of concurrent requests with an additive-increase/multiplicative-decrease
+// (AIMD) limit: the limit grows by one for every limit successful requests and is multiplied by BackoffRatio when
+// the service throttles. Throttled responses of requests sent before the last cut don't cut it again. It is safe
+// for concurrent use; share one limiter between all clients using the same resource.
+type AdaptiveLimiter struct {
+	config AdaptiveLimit
+
+	mu       sync.Mutex
+	limit    float64
+	epoch    uint64
+	changed  chan struct{}
+	inFlight int
+	waiting  int
+	stats    AdaptiveLimiterStats
+}
+
+// NewAdaptiveLimiter creates an AdaptiveLimiter. Zero fields of config take the value of DefaultAdaptiveLimit.
+func NewAdaptiveLimiter(config AdaptiveLimit) *AdaptiveLimiter {
+	if config.MinLimit <= 0 {
+		config.MinLimit = DefaultAdaptiveLimit.MinLimit
+	}
+	if config.MaxLimit <= 0 {
+		config.MaxLimit = DefaultAdaptiveLimit.MaxLimit
+	}
+	if config.MaxLimit < config.MinLimit {
+		config.MaxLimit = config.MinLimit
+	}
+	if config.InitialLimit <= 0 {
+		config.InitialLimit = DefaultAdaptiveLimit.InitialLimit
+	}
+	if config.BackoffRatio <= 0 || config.BackoffRatio >= 1 {
+		config.BackoffRatio = DefaultAdaptiveLimit.BackoffRatio
+	}
+	limit := math.Max(float64(config.MinLimit), math.Min(float64(config.MaxLimit), float64(config.InitialLimit)))
+	return &AdaptiveLimiter{config: config, limit: limit, changed: make(chan struct{})}
+}
+
+// Acquire waits for a request slot. The returned function must be called with the outcome of the request once it
+// completes.
+func (l *AdaptiveLimiter) Acquire(ctx context.Context) (func(outcome AdaptiveOutcome), error) {
+	l.mu.Lock()
+	for l.inFlight >= int(l.limit) {
+		changed := l.changed
+		l.waiting++
+		l.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			l.mu.Lock()
+			l.waiting--
+			l.mu.Unlock()
+			return nil, ctx.Err()
+		case <-changed:
+		}
+		l.mu.Lock()
+		l.waiting--
+	}
+	l.inFlight++
+	epoch := l.epoch
+	l.mu.Unlock()
+
+	var once sync.Once
+	return func(outcome AdaptiveOutcome) {
+		once.Do(func() { l.release(epoch, outcome) })
+	}, nil
+}
+
+func (l *AdaptiveLimiter) release(epoch uint64, outcome AdaptiveOutcome) {
+	l.mu.Lock()
+	l.inFlight--
+	switch outcome {
+	case AdaptiveOutcomeSuccess:
+		l.stats.Successes++
+		l.limit = math.Min(float64(l.config.MaxLimit), l.limit+1/l.limit)
+	case AdaptiveOutcomeThrottled:
+		l.stats.Throttles++
+		if epoch == l.epoch {
+			l.limit = math.Max(float64(l.config.MinLimit), math.Floor(l.limit*l.config.BackoffRatio))
+			l.epoch++
+			l.stats.Decreases++
+		}
+	}
+	close(l.changed)
+	l.changed = make(chan struct{})
+	stats := l.snapshot()
+	l.mu.Unlock()
+
+	if outcome == AdaptiveOutcomeThrottled && l.config.OnThrottle != nil {
+		l.config.OnThrottle(stats)
+	}
+}
+
+// Stats returns a snapshot of the limiter.
+func (l *AdaptiveLimiter) Stats() AdaptiveLimiterStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.snapshot()
+}
+
+func (l *AdaptiveLimiter) snapshot() AdaptiveLimiterStats {
+	stats := l.stats
+	stats.Limit = int(l.limit)
+	stats.InFlight = l.inFlight
+	stats.Waiting = l.waiting
+	return stats
+}
@@ -0,0 +1,112 @@
+package v20230401_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/kde713/azurelangai-go/textanalysis/v20230401"
+)
+
+func TestAdaptiveLimiter_Acquire(t *testing.T) {
+	limiter := v20230401.NewAdaptiveLimiter(v20230401.AdaptiveLimit{InitialLimit: 2})
+	release1, err := limiter.Acquire(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := limiter.Acquire(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := limiter.Acquire(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context.DeadlineExceeded, got %v", err)
+	}
+
+	acquired := make(chan error)
+	go func() {
+		_, err := limiter.Acquire(context.TODO())
+		acquired <- err
+	}()
+	release1(v20230401.AdaptiveOutcomeIgnore)
+	if err := <-acquired; err != nil {
+		t.Fatal(err)
+	}
+	if stats := limiter.Stats(); stats.InFlight != 2 || stats.Waiting != 0 {
+		t.Errorf("Expected 2 in flight and none waiting, got %+v", stats)
+	}
+}
+
+func TestAdaptiveLimiter_AIMD(t *testing.T) {
+	var throttles []v20230401.AdaptiveLimiterStats
+	limiter := v20230401.NewAdaptiveLimiter(v20230401.AdaptiveLimit{
+		InitialLimit: 4,
+		MinLimit:     1,
+		MaxLimit:     5,
+		BackoffRatio: 0.5,
+		OnThrottle: func(stats v20230401.AdaptiveLimiterStats) {
+			throttles = append(throttles, stats)
+		},
+	})
+
+	// Additive increase: about one per limit successes, bounded by MaxLimit.
+	for i := 0; i < 20; i++ {
+		release, err := limiter.Acquire(context.TODO())
+		if err != nil {
+			t.Fatal(err)
+		}
+		release(v20230401.AdaptiveOutcomeSuccess)
+	}
+	if stats := limiter.Stats(); stats.Limit != 5 || stats.Successes != 20 {
+		t.Fatalf("Expected limit 5 after 20 successes, got %+v", stats)
+	}
+
+	// Multiplicative decrease: requests in flight at the time of the cut don't cut the limit again.
+	releases := make([]func(v20230401.AdaptiveOutcome), 5)
+	for i := range releases {
+		release, err := limiter.Acquire(context.TODO())
+		if err != nil {
+			t.Fatal(err)
+		}
+		releases[i] = release
+	}
+	for _, release := range releases {
+		release(v20230401.AdaptiveOutcomeThrottled)
+	}
+	stats := limiter.Stats()
+	if stats.Limit != 2 {
+		t.Errorf("Expected limit 2, got %d", stats.Limit)
+	}
+	if stats.Throttles != 5 || stats.Decreases != 1 {
+		t.Errorf("Expected 5 throttles and 1 decrease, got %+v", stats)
+	}
+	if len(throttles) != 5 {
+		t.Errorf("Expected 5 OnThrottle calls, got %d", len(throttles))
+	}
+}
+
+func TestWithAdaptiveLimiter(t *testing.T) {
+	srv, requests := newFlakyServer(t, 1, http.StatusTooManyRequests, nil, "", nil)
+	limiter := v20230401.NewAdaptiveLimiter(v20230401.DefaultAdaptiveLimit)
+	client := v20230401.NewClient(srv.URL, "key",
+		v20230401.WithAdaptiveLimiter(limiter),
+		v20230401.WithRetryCount(1, time.Millisecond, time.Millisecond),
+	)
+
+	if _, err := client.GetTextAnalyticsJobResult(context.TODO(), "job-1"); err != nil {
+		t.Fatal(err)
+	}
+	if *requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", *requests)
+	}
+	stats := limiter.Stats()
+	if stats.Throttles != 1 || stats.Successes != 1 || stats.InFlight != 0 {
+		t.Errorf("Expected 1 throttle, 1 success and nothing in flight, got %+v", stats)
+	}
+	if stats.Limit != 2 {
+		t.Errorf("Expected limit 2, got %d", stats.Limit)
+	}
+}
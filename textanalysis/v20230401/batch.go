@@ -0,0 +1,355 @@
+package v20230401
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"unicode/utf8"
+)
+
+// MaxDocumentCharacters is the maximum number of characters of a single document accepted by synchronous calls.
+const MaxDocumentCharacters = 5120
+
+// BatchLimits describes how many documents can be sent in a single synchronous analyze-text request.
+type BatchLimits struct {
+	// MaxDocuments Maximum number of documents per request.
+	MaxDocuments int
+	// MaxCharacters Maximum number of characters across all documents of a request.
+	MaxCharacters int
+}
+
+// DefaultBatchLimits are the service data limits of synchronous requests. See https://aka.ms/language-limits.
+var DefaultBatchLimits = map[TaskKind]BatchLimits{
+	TaskKindLanguageDetection:    {MaxDocuments: 1000, MaxCharacters: 125000},
+	TaskKindEntityRecognition:    {MaxDocuments: 5, MaxCharacters: 125000},
+	TaskKindKeyPhraseExtraction:  {MaxDocuments: 10, MaxCharacters: 125000},
+	TaskKindSentimentAnalysis:    {MaxDocuments: 10, MaxCharacters: 125000},
+	TaskKindPiiEntityRecognition: {MaxDocuments: 5, MaxCharacters: 125000},
+	TaskKindEntityLinking:        {MaxDocuments: 5, MaxCharacters: 125000},
+}
+
+type batchOptions struct {
+	concurrency int
+	limits      map[TaskKind]BatchLimits
+}
+
+type BatchOption func(*batchOptions)
+
+// WithBatchConcurrency sets how many batches are sent at the same time. Defaults to 4.
+func WithBatchConcurrency(concurrency int) BatchOption {
+	return func(o *batchOptions) {
+		o.concurrency = concurrency
+	}
+}
+
+// WithBatchLimits overrides the batch limits of a task kind.
+func WithBatchLimits(kind TaskKind, limits BatchLimits) BatchOption {
+	return func(o *batchOptions) {
+		o.limits[kind] = limits
+	}
+}
+
+var _ Client = (*batchingClient)(nil)
+
+type batchingClient struct {
+	Client
+	concurrency int
+	limits      map[TaskKind]BatchLimits
+}
+
+// NewBatchingClient wraps c so that synchronous analyze-text calls split their documents into batches that respect
+// the service limits. Batches run concurrently and their documents, errors and statistics are merged back into a
+// single result ordered like the input. Job operations are passed through unchanged.
+func NewBatchingClient(c Client, optAppliers ...BatchOption) Client {
+	o := batchOptions{
+		concurrency: 4,
+		limits:      make(map[TaskKind]BatchLimits, len(DefaultBatchLimits)),
+	}
+	for kind, limits := range DefaultBatchLimits {
+		o.limits[kind] = limits
+	}
+	for _, applier := range optAppliers {
+		applier(&o)
+	}
+	if o.concurrency < 1 {
+		o.concurrency = 1
+	}
+	return &batchingClient{
+		Client:      c,
+		concurrency: o.concurrency,
+		limits:      o.limits,
+	}
+}
+
+func (b *batchingClient) AnalyzeTextLanguageDetection(ctx context.Context, input LanguageDetectionAnalysisInput, parameters LanguageDetectionTaskParameters) (*LanguageDetectionResult, error) {
+	return mergeBatches(ctx, b, TaskKindLanguageDetection, input.Documents, languageInputText, languageInputID, languageDetectionBatchFields, func(ctx context.Context, docs []LanguageInput) (*LanguageDetectionResult, error) {
+		return b.Client.AnalyzeTextLanguageDetection(ctx, LanguageDetectionAnalysisInput{Documents: docs}, parameters)
+	})
+}
+
+func (b *batchingClient) AnalyzeTextEntityRecognition(ctx context.Context, input MultiLanguageAnalysisInput, parameters EntitiesTaskParameters) (*EntitiesResult, error) {
+	return mergeBatches(ctx, b, TaskKindEntityRecognition, input.Documents, multiLanguageInputText, multiLanguageInputID, entitiesBatchFields, func(ctx context.Context, docs []MultiLanguageInput) (*EntitiesResult, error) {
+		return b.Client.AnalyzeTextEntityRecognition(ctx, MultiLanguageAnalysisInput{Documents: docs}, parameters)
+	})
+}
+
+func (b *batchingClient) AnalyzeTextKeyPhraseExtraction(ctx context.Context, input MultiLanguageAnalysisInput, parameters KeyPhraseTaskParameters) (*KeyPhraseResult, error) {
+	return mergeBatches(ctx, b, TaskKindKeyPhraseExtraction, input.Documents, multiLanguageInputText, multiLanguageInputID, keyPhraseBatchFields, func(ctx context.Context, docs []MultiLanguageInput) (*KeyPhraseResult, error) {
+		return b.Client.AnalyzeTextKeyPhraseExtraction(ctx, MultiLanguageAnalysisInput{Documents: docs}, parameters)
+	})
+}
+
+// AnalyzeTextSentimentAnalysis merges sentiment batches. The document index of opinion mining references is not
+// rewritten; use SentimentAnalyzedDocument.MinedOpinions, which resolves references within the document.
+func (b *batchingClient) AnalyzeTextSentimentAnalysis(ctx context.Context, input MultiLanguageAnalysisInput, parameters SentimentAnalysisTaskParameters) (*SentimentResponse, error) {
+	return mergeBatches(ctx, b, TaskKindSentimentAnalysis, input.Documents, multiLanguageInputText, multiLanguageInputID, sentimentBatchFields, func(ctx context.Context, docs []MultiLanguageInput) (*SentimentResponse, error) {
+		return b.Client.AnalyzeTextSentimentAnalysis(ctx, MultiLanguageAnalysisInput{Documents: docs}, parameters)
+	})
+}
+
+func (b *batchingClient) AnalyzeTextPiiEntityRecognition(ctx context.Context, input MultiLanguageAnalysisInput, parameters PiiTaskParameters) (*PiiResult, error) {
+	return mergeBatches(ctx, b, TaskKindPiiEntityRecognition, input.Documents, multiLanguageInputText, multiLanguageInputID, piiBatchFields, func(ctx context.Context, docs []MultiLanguageInput) (*PiiResult, error) {
+		return b.Client.AnalyzeTextPiiEntityRecognition(ctx, MultiLanguageAnalysisInput{Documents: docs}, parameters)
+	})
+}
+
+func (b *batchingClient) AnalyzeTextEntityLinking(ctx context.Context, input MultiLanguageAnalysisInput, parameters EntityLinkingTaskParameters) (*EntityLinkingResult, error) {
+	return mergeBatches(ctx, b, TaskKindEntityLinking, input.Documents, multiLanguageInputText, multiLanguageInputID, entityLinkingBatchFields, func(ctx context.Context, docs []MultiLanguageInput) (*EntityLinkingResult, error) {
+		return b.Client.AnalyzeTextEntityLinking(ctx, MultiLanguageAnalysisInput{Documents: docs}, parameters)
+	})
+}
+
+// batchFields gives mergeBatches access to the fields of a result.
+type batchFields[Document any, Error any] struct {
+	documents    *[]Document
+	documentID   func(Document) string
+	errors       *[]Error
+	errorID      func(Error) string
+	modelVersion *string
+	statistics   **RequestStatistics
+}
+
+// mergeBatches sends the batches of docs and merges the documents, errors and statistics of their results into a
+// single result ordered like docs.
+func mergeBatches[Doc any, Result any, Document any, Error any](ctx context.Context, b *batchingClient, kind TaskKind, docs []Doc, text func(Doc) string, id func(Doc) string, fields func(*Result) batchFields[Document, Error], call func(context.Context, []Doc) (*Result, error)) (*Result, error) {
+	parts, err := runBatches(ctx, b, kind, docs, text, call)
+	if len(parts) <= 1 {
+		return firstPart(parts, err)
+	}
+	merged := new(Result)
+	m := fields(merged)
+	for _, part := range parts {
+		p := fields(part)
+		*m.documents = append(*m.documents, *p.documents...)
+		*m.errors = append(*m.errors, *p.errors...)
+		*m.modelVersion = *p.modelVersion
+		*m.statistics = mergeRequestStatistics(*m.statistics, *p.statistics)
+	}
+	order := inputOrder(docs, id)
+	sortByInputOrder(*m.documents, order, m.documentID)
+	sortByInputOrder(*m.errors, order, m.errorID)
+	return merged, err
+}
+
+func languageDetectionBatchFields(r *LanguageDetectionResult) batchFields[LanguageDetectionDocumentResult, InputError] {
+	return batchFields[LanguageDetectionDocumentResult, InputError]{
+		documents:    &r.Documents,
+		documentID:   func(d LanguageDetectionDocumentResult) string { return d.ID },
+		errors:       &r.Errors,
+		errorID:      func(e InputError) string { return e.ID },
+		modelVersion: &r.ModelVersion,
+		statistics:   &r.Statistics,
+	}
+}
+
+func entitiesBatchFields(r *EntitiesResult) batchFields[EntityRecognizedDocument, DocumentError] {
+	return batchFields[EntityRecognizedDocument, DocumentError]{
+		documents:    &r.Documents,
+		documentID:   func(d EntityRecognizedDocument) string { return d.ID },
+		errors:       &r.Errors,
+		errorID:      documentErrorID,
+		modelVersion: &r.ModelVersion,
+		statistics:   &r.Statistics,
+	}
+}
+
+func keyPhraseBatchFields(r *KeyPhraseResult) batchFields[KeyPhrasesExtractedDocument, DocumentError] {
+	return batchFields[KeyPhrasesExtractedDocument, DocumentError]{
+		documents:    &r.Documents,
+		documentID:   func(d KeyPhrasesExtractedDocument) string { return d.ID },
+		errors:       &r.Errors,
+		errorID:      documentErrorID,
+		modelVersion: &r.ModelVersion,
+		statistics:   &r.Statistics,
+	}
+}
+
+func sentimentBatchFields(r *SentimentResponse) batchFields[SentimentAnalyzedDocument, DocumentError] {
+	return batchFields[SentimentAnalyzedDocument, DocumentError]{
+		documents:    &r.Documents,
+		documentID:   func(d SentimentAnalyzedDocument) string { return d.ID },
+		errors:       &r.Errors,
+		errorID:      documentErrorID,
+		modelVersion: &r.ModelVersion,
+		statistics:   &r.Statistics,
+	}
+}
+
+func piiBatchFields(r *PiiResult) batchFields[PiiEntitiesDocumentResult, DocumentError] {
+	return batchFields[PiiEntitiesDocumentResult, DocumentError]{
+		documents:    &r.Documents,
+		documentID:   func(d PiiEntitiesDocumentResult) string { return d.ID },
+		errors:       &r.Errors,
+		errorID:      documentErrorID,
+		modelVersion: &r.ModelVersion,
+		statistics:   &r.Statistics,
+	}
+}
+
+func entityLinkingBatchFields(r *EntityLinkingResult) batchFields[LinkedEntitiesDocumentResult, DocumentError] {
+	return batchFields[LinkedEntitiesDocumentResult, DocumentError]{
+		documents:    &r.Documents,
+		documentID:   func(d LinkedEntitiesDocumentResult) string { return d.ID },
+		errors:       &r.Errors,
+		errorID:      documentErrorID,
+		modelVersion: &r.ModelVersion,
+		statistics:   &r.Statistics,
+	}
+}
+
+// splitBatches groups documents into consecutive batches that respect limits. A document exceeding
+// MaxCharacters on its own is sent alone and left to the service to reject.
+func splitBatches[Doc any](docs []Doc, limits BatchLimits, text func(Doc) string) [][]Doc {
+	var batches [][]Doc
+	start, characters := 0, 0
+	for i, doc := range docs {
+		n := utf8.RuneCountInString(text(doc))
+		full := limits.MaxDocuments > 0 && i-start >= limits.MaxDocuments
+		tooLong := limits.MaxCharacters > 0 && characters+n > limits.MaxCharacters
+		if i > start && (full || tooLong) {
+			batches = append(batches, docs[start:i])
+			start, characters = i, 0
+		}
+		characters += n
+	}
+	if start < len(docs) || len(docs) == 0 {
+		batches = append(batches, docs[start:])
+	}
+	return batches
+}
+
+// runBatches sends the batches of docs with bounded concurrency and returns their results in batch order.
+// The first failing batch cancels the remaining ones and its error is returned without results. Batches failing with
+// *DocumentErrors keep their results, and their document errors are returned joined together with all results.
+func runBatches[Doc any, Result any](ctx context.Context, b *batchingClient, kind TaskKind, docs []Doc, text func(Doc) string, call func(context.Context, []Doc) (*Result, error)) ([]*Result, error) {
+	batches := splitBatches(docs, b.limits[kind], text)
+	if len(batches) == 1 {
+		result, err := call(ctx, batches[0])
+		var docErrs *DocumentErrors
+		if err != nil && !errors.As(err, &docErrs) {
+			return nil, err
+		}
+		return []*Result{result}, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]*Result, len(batches))
+	batchDocErrs := make([]*DocumentErrors, len(batches))
+	sem := make(chan struct{}, b.concurrency)
+	var wg sync.WaitGroup
+	var errOnce sync.Once
+	var firstErr error
+	for i := range batches {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			result, err := call(ctx, batches[i])
+			if err != nil && !errors.As(err, &batchDocErrs[i]) {
+				errOnce.Do(func() {
+					firstErr = err
+					cancel()
+				})
+				return
+			}
+			results[i] = result
+		}(i)
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var joined *DocumentErrors
+	for _, docErrs := range batchDocErrs {
+		if docErrs == nil {
+			continue
+		}
+		if joined == nil {
+			joined = &DocumentErrors{}
+		}
+		joined.Errors = append(joined.Errors, docErrs.Errors...)
+	}
+	if joined != nil {
+		return results, joined
+	}
+	return results, nil
+}
+
+// firstPart returns the result of a single batch, if any, together with err.
+func firstPart[Result any](parts []*Result, err error) (*Result, error) {
+	if len(parts) == 0 {
+		return nil, err
+	}
+	return parts[0], err
+}
+
+func inputOrder[Doc any](docs []Doc, id func(Doc) string) map[string]int {
+	order := make(map[string]int, len(docs))
+	for i, doc := range docs {
+		order[id(doc)] = i
+	}
+	return order
+}
+
+func sortByInputOrder[T any](items []T, order map[string]int, id func(T) string) {
+	sort.SliceStable(items, func(i, j int) bool {
+		return order[id(items[i])] < order[id(items[j])]
+	})
+}
+
+func mergeRequestStatistics(merged *RequestStatistics, part *RequestStatistics) *RequestStatistics {
+	if part == nil {
+		return merged
+	}
+	if merged == nil {
+		merged = &RequestStatistics{}
+	}
+	merged.DocumentsCount += part.DocumentsCount
+	merged.ValidDocumentsCount += part.ValidDocumentsCount
+	merged.ErroneousDocumentsCount += part.ErroneousDocumentsCount
+	merged.TransactionsCount += part.TransactionsCount
+	return merged
+}
+
+func languageInputText(d LanguageInput) string { return d.Text }
+
+func languageInputID(d LanguageInput) string { return d.ID }
+
+func multiLanguageInputText(d MultiLanguageInput) string { return d.Text }
+
+func multiLanguageInputID(d MultiLanguageInput) string { return d.ID }
+
+func documentErrorID(e DocumentError) string { return e.ID }
@@ -0,0 +1,186 @@
+package v20230401_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/kde713/azurelangai-go/textanalysis/v20230401"
+)
+
+// newEchoEntityServer answers entity recognition requests with one entity per document, returning documents in
+// reverse order and an error for documents whose text is empty.
+func newEchoEntityServer(t *testing.T, batchSizes *[]int) *httptest.Server {
+	var mu sync.Mutex
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body v20230401.RequestBody[v20230401.MultiLanguageAnalysisInput, v20230401.EntitiesTaskParameters]
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error(err)
+		}
+		mu.Lock()
+		*batchSizes = append(*batchSizes, len(body.AnalysisInput.Documents))
+		mu.Unlock()
+
+		result := v20230401.EntitiesResult{ModelVersion: "2021-06-01", Statistics: &v20230401.RequestStatistics{}}
+		for i := len(body.AnalysisInput.Documents) - 1; i >= 0; i-- {
+			doc := body.AnalysisInput.Documents[i]
+			result.Statistics.DocumentsCount++
+			if doc.Text == "" {
+				result.Errors = append(result.Errors, v20230401.DocumentError{ID: doc.ID, Error: v20230401.ErrorInformation{Code: "InvalidDocument"}})
+				continue
+			}
+			result.Documents = append(result.Documents, v20230401.EntityRecognizedDocument{
+				ID:       doc.ID,
+				Entities: []v20230401.Entity{{Text: doc.Text, Category: "Test"}},
+			})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(v20230401.TaskResponse[v20230401.EntitiesResult]{Kind: "EntityRecognitionResults", Results: result})
+	}))
+}
+
+func TestNewBatchingClient(t *testing.T) {
+	var batchSizes []int
+	srv := newEchoEntityServer(t, &batchSizes)
+	defer srv.Close()
+	client := v20230401.NewBatchingClient(v20230401.NewClient(srv.URL, "key"), v20230401.WithBatchConcurrency(3))
+
+	input := v20230401.MultiLanguageAnalysisInput{}
+	for i := 0; i < 23; i++ {
+		text := fmt.Sprintf("document %d", i)
+		if i%7 == 3 {
+			text = ""
+		}
+		input.Documents = append(input.Documents, v20230401.MultiLanguageInput{ID: fmt.Sprint(i), Text: text})
+	}
+	result, err := client.AnalyzeTextEntityRecognition(context.TODO(), input, v20230401.EntitiesTaskParameters{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(batchSizes) != 5 {
+		t.Errorf("Expected 5 batches, got %v", batchSizes)
+	}
+	for _, size := range batchSizes {
+		if size > v20230401.DefaultBatchLimits[v20230401.TaskKindEntityRecognition].MaxDocuments {
+			t.Errorf("Batch exceeds the document limit: %v", batchSizes)
+		}
+	}
+	if len(result.Documents) != 20 || len(result.Errors) != 3 {
+		t.Fatalf("Expected 20 documents and 3 errors, got %d and %d", len(result.Documents), len(result.Errors))
+	}
+	var ids []string
+	for _, doc := range result.Documents {
+		ids = append(ids, doc.ID)
+	}
+	if got := strings.Join(ids, ","); got != "0,1,2,4,5,6,7,8,9,11,12,13,14,15,16,18,19,20,21,22" {
+		t.Errorf("Documents are not in input order: %s", got)
+	}
+	if result.Errors[0].ID != "3" || result.Errors[1].ID != "10" || result.Errors[2].ID != "17" {
+		t.Errorf("Errors are not in input order: %+v", result.Errors)
+	}
+	if result.Statistics == nil || result.Statistics.DocumentsCount != 23 {
+		t.Errorf("Expected merged statistics of 23 documents, got %+v", result.Statistics)
+	}
+}
+
+func TestNewBatchingClient_CharacterLimit(t *testing.T) {
+	var batchSizes []int
+	srv := newEchoEntityServer(t, &batchSizes)
+	defer srv.Close()
+	client := v20230401.NewBatchingClient(
+		v20230401.NewClient(srv.URL, "key"),
+		v20230401.WithBatchLimits(v20230401.TaskKindEntityRecognition, v20230401.BatchLimits{MaxDocuments: 5, MaxCharacters: 10}),
+	)
+
+	// Hangul syllables count as one character each.
+	input := v20230401.MultiLanguageAnalysisInput{Documents: []v20230401.MultiLanguageInput{
+		{ID: "1", Text: "안녕하세요"},
+		{ID: "2", Text: "반갑습니다"},
+		{ID: "3", Text: "감사합니다"},
+	}}
+	result, err := client.AnalyzeTextEntityRecognition(context.TODO(), input, v20230401.EntitiesTaskParameters{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(batchSizes) != 2 {
+		t.Errorf("Expected 2 batches, got %v", batchSizes)
+	}
+	if len(result.Documents) != 3 || result.Documents[0].ID != "1" || result.Documents[2].ID != "3" {
+		t.Errorf("Unexpected documents: %+v", result.Documents)
+	}
+}
+
+func TestNewBatchingClient_StrictDocumentErrors(t *testing.T) {
+	var batchSizes []int
+	srv := newEchoEntityServer(t, &batchSizes)
+	defer srv.Close()
+	client := v20230401.NewBatchingClient(v20230401.NewClient(srv.URL, "key", v20230401.WithStrictDocumentErrors()), v20230401.WithBatchConcurrency(2))
+
+	input := v20230401.MultiLanguageAnalysisInput{}
+	for i := 0; i < 12; i++ {
+		text := fmt.Sprintf("document %d", i)
+		if i == 1 || i == 10 {
+			text = ""
+		}
+		input.Documents = append(input.Documents, v20230401.MultiLanguageInput{ID: fmt.Sprint(i), Text: text})
+	}
+	result, err := client.AnalyzeTextEntityRecognition(context.TODO(), input, v20230401.EntitiesTaskParameters{})
+	var docErrs *v20230401.DocumentErrors
+	if !errors.As(err, &docErrs) {
+		t.Fatalf("Expected DocumentErrors, got %v", err)
+	}
+	if ids := strings.Join(docErrs.IDs(), ","); ids != "1,10" {
+		t.Errorf("Expected failed IDs 1,10, got %s", ids)
+	}
+	if len(batchSizes) != 3 {
+		t.Errorf("Expected 3 batches, got %v", batchSizes)
+	}
+	if result == nil || len(result.Documents) != 10 || len(result.Errors) != 2 {
+		t.Fatalf("Expected merged result with 10 documents and 2 errors, got %+v", result)
+	}
+}
+
+func TestNewChunkingClient_StrictDocumentErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body v20230401.RequestBody[v20230401.MultiLanguageAnalysisInput, v20230401.EntitiesTaskParameters]
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error(err)
+		}
+		// The second chunk of every split document fails.
+		result := v20230401.EntitiesResult{ModelVersion: "2021-06-01"}
+		for _, doc := range body.AnalysisInput.Documents {
+			if strings.HasSuffix(doc.ID, "#1") {
+				result.Errors = append(result.Errors, v20230401.DocumentError{ID: doc.ID, Error: v20230401.ErrorInformation{Code: "InvalidDocument"}})
+				continue
+			}
+			result.Documents = append(result.Documents, v20230401.EntityRecognizedDocument{ID: doc.ID})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(v20230401.TaskResponse[v20230401.EntitiesResult]{Kind: "EntityRecognitionResults", Results: result})
+	}))
+	defer srv.Close()
+	client := v20230401.NewChunkingClient(v20230401.NewClient(srv.URL, "key", v20230401.WithStrictDocumentErrors()),
+		v20230401.DocumentChunker{MaxCharacters: 30})
+
+	input := v20230401.MultiLanguageAnalysisInput{Documents: []v20230401.MultiLanguageInput{
+		{ID: "short", Text: "Short document."},
+		{ID: "long", Text: "First paragraph here.\n\nSecond paragraph."},
+	}}
+	result, err := client.AnalyzeTextEntityRecognition(context.TODO(), input, v20230401.EntitiesTaskParameters{})
+	var docErrs *v20230401.DocumentErrors
+	if !errors.As(err, &docErrs) {
+		t.Fatalf("Expected DocumentErrors, got %v", err)
+	}
+	if ids := strings.Join(docErrs.IDs(), ","); ids != "long" {
+		t.Errorf("Expected failed IDs long, got %s", ids)
+	}
+	if result == nil || len(result.Documents) != 1 || result.Documents[0].ID != "short" || len(result.Errors) != 1 {
+		t.Fatalf("Expected stitched result with document short and 1 error, got %+v", result)
+	}
+}
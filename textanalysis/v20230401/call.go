@@ -0,0 +1,200 @@
+package v20230401
+
+import (
+	"context"
+	"net/http"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-resty/resty/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Operations of Client calls, as reported to spans and metrics.
+const (
+	OperationAnalyzeText = "AnalyzeText"
+	OperationSubmitJob   = "SubmitJob"
+	OperationGetJob      = "GetJob"
+	OperationCancelJob   = "CancelJob"
+)
+
+type callContextKey struct{}
+
+// call observes a Client call for tracing and metrics. A nil *call observes nothing.
+type call struct {
+	span    trace.Span
+	metrics MetricsSink
+	start   time.Time
+	m       CallMetrics
+}
+
+// startCall starts observing a Client call. input is the analysis input of the call, if any.
+func (c client) startCall(ctx context.Context, operation string, kind TaskKind, input analysisInput, attrs ...attribute.KeyValue) (context.Context, *call) {
+	if c.tracer == nil && c.metrics == nil {
+		return ctx, nil
+	}
+	cl := &call{
+		metrics: c.metrics,
+		start:   time.Now(),
+		m:       CallMetrics{Operation: operation, TaskKind: kind},
+	}
+	if input != nil {
+		cl.m.Documents = input.documentCount()
+		cl.m.Characters = input.characterCount()
+	}
+	if c.tracer != nil {
+		attrs = append(attrs,
+			attrNamespace.String("Microsoft.CognitiveServices"),
+			attrAPIVersion.String(APIVersion))
+		if kind != "" {
+			attrs = append(attrs, attrTaskKind.String(string(kind)))
+		}
+		if input != nil {
+			attrs = append(attrs, attrDocumentCount.Int(cl.m.Documents))
+		}
+		ctx, cl.span = c.tracer.Start(ctx, "Language."+operation, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+	}
+	return context.WithValue(ctx, callContextKey{}, cl), cl
+}
+
+func callFromContext(ctx context.Context) *call {
+	cl, _ := ctx.Value(callContextKey{}).(*call)
+	return cl
+}
+
+// observeAttempt records the response to a request sent for attempt of the retry policy, starting at 1. A request
+// resent with another key after 401 belongs to the attempt of the rejected one.
+func (cl *call) observeAttempt(resp *resty.Response, attempt int) {
+	if cl == nil {
+		return
+	}
+	cl.m.Attempts = attempt
+	cl.m.StatusCode = 0
+	if resp == nil || resp.RawResponse == nil {
+		return
+	}
+	cl.m.StatusCode = resp.StatusCode()
+	// Throttled like the adaptive limiter counts them.
+	if cl.m.StatusCode == http.StatusTooManyRequests || cl.m.StatusCode == http.StatusServiceUnavailable {
+		cl.m.Throttles++
+	}
+	if requestID := resp.Header().Get("apim-request-id"); requestID != "" {
+		cl.m.RequestID = requestID
+	}
+}
+
+// setAttributes adds attributes to the span of the call.
+func (cl *call) setAttributes(attrs ...attribute.KeyValue) {
+	if cl == nil || cl.span == nil {
+		return
+	}
+	cl.span.SetAttributes(attrs...)
+}
+
+// end finishes observing the call. result is the result returned by the call, if it has documents, including the
+// partial result returned with *DocumentErrors.
+func (cl *call) end(result documentErrorLister, err error) {
+	if cl == nil {
+		return
+	}
+	cl.m.Duration = time.Since(cl.start)
+	cl.m.Err = err
+	if result != nil {
+		cl.m.DocumentErrors = len(result.documentErrors())
+	}
+	if cl.span != nil {
+		cl.span.SetAttributes(attrRetryCount.Int(cl.m.Retries()))
+		if cl.m.StatusCode != 0 {
+			cl.span.SetAttributes(attrHTTPStatusCode.Int(cl.m.StatusCode))
+		}
+		if cl.m.RequestID != "" {
+			cl.span.SetAttributes(attrRequestID.String(cl.m.RequestID))
+		}
+		if result != nil {
+			cl.span.SetAttributes(attrDocumentErrorCount.Int(cl.m.DocumentErrors))
+		}
+		if err != nil {
+			cl.span.RecordError(err)
+			cl.span.SetStatus(codes.Error, err.Error())
+		}
+		cl.span.End()
+	}
+	if cl.metrics != nil {
+		cl.metrics.RecordCall(cl.m)
+	}
+}
+
+// analysisInput is implemented by the analysis inputs of requests.
+type analysisInput interface {
+	documentCount() int
+	characterCount() int
+}
+
+func (i LanguageDetectionAnalysisInput) documentCount() int { return len(i.Documents) }
+
+func (i LanguageDetectionAnalysisInput) characterCount() int {
+	count := 0
+	for _, doc := range i.Documents {
+		count += utf8.RuneCountInString(doc.Text)
+	}
+	return count
+}
+
+func (i MultiLanguageAnalysisInput) documentCount() int { return len(i.Documents) }
+
+func (i MultiLanguageAnalysisInput) characterCount() int {
+	count := 0
+	for _, doc := range i.Documents {
+		count += utf8.RuneCountInString(doc.Text)
+	}
+	return count
+}
+
+// documentErrorLister is implemented by results to report their document errors.
+type documentErrorLister interface {
+	documentErrors() []DocumentError
+}
+
+func (r LanguageDetectionResult) documentErrors() []DocumentError {
+	if len(r.Errors) == 0 {
+		return nil
+	}
+	errs := make([]DocumentError, len(r.Errors))
+	for i, inputErr := range r.Errors {
+		errs[i] = DocumentError{Error: inputErr.Error, ID: inputErr.ID}
+	}
+	return errs
+}
+
+func (r EntitiesResult) documentErrors() []DocumentError { return r.Errors }
+
+func (r KeyPhraseResult) documentErrors() []DocumentError { return r.Errors }
+
+func (r SentimentResponse) documentErrors() []DocumentError { return r.Errors }
+
+func (r PiiResult) documentErrors() []DocumentError { return r.Errors }
+
+func (r EntityLinkingResult) documentErrors() []DocumentError { return r.Errors }
+
+func (r ExtractiveSummarizationResult) documentErrors() []DocumentError { return r.Errors }
+
+func (r AbstractiveSummarizationResult) documentErrors() []DocumentError { return r.Errors }
+
+func (r HealthcareResult) documentErrors() []DocumentError { return r.Errors }
+
+func (r CustomEntitiesResult) documentErrors() []DocumentError { return r.Errors }
+
+func (r CustomLabelClassificationResult) documentErrors() []DocumentError { return r.Errors }
+
+// documentErrors collects the document errors of the completed tasks of the job.
+func (r JobStatusResponse) documentErrors() []DocumentError {
+	var errs []DocumentError
+	for _, item := range r.Tasks.Items {
+		if lister, ok := item.Results.(documentErrorLister); ok {
+			errs = append(errs, lister.documentErrors()...)
+		}
+	}
+	return errs
+}
@@ -0,0 +1,438 @@
+package v20230401
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DocumentChunker splits documents longer than the per-document limit into chunks at paragraph or sentence
+// boundaries so they can be analyzed separately and stitched back together.
+type DocumentChunker struct {
+	// MaxCharacters Maximum number of characters per chunk. Defaults to MaxDocumentCharacters.
+	MaxCharacters int
+}
+
+type documentChunk struct {
+	originalID string
+	index      int
+	// start Byte offset of the chunk in the original text.
+	start int
+}
+
+// ChunkedInput is the result of DocumentChunker.Split. Input holds the documents to send to the service and the
+// Stitch methods map the results back to the original documents. The Stitch methods reuse and modify the slices of
+// the result passed to them.
+type ChunkedInput struct {
+	Input MultiLanguageAnalysisInput
+
+	original        map[string]MultiLanguageInput
+	order           map[string]int
+	chunkOrder      map[string]int
+	chunks          map[string]documentChunk
+	stringIndexType StringIndexType
+}
+
+// Split splits every document of input that is longer than MaxCharacters. Chunks are given the ID
+// "<original ID>#<chunk index>"; shorter documents are kept unchanged. stringIndexType must be the value sent
+// with the task parameters, because the offsets of stitched results are rewritten in that unit.
+func (ch DocumentChunker) Split(input MultiLanguageAnalysisInput, stringIndexType StringIndexType) *ChunkedInput {
+	maxCharacters := ch.MaxCharacters
+	if maxCharacters <= 0 {
+		maxCharacters = MaxDocumentCharacters
+	}
+	chunked := &ChunkedInput{
+		original:        make(map[string]MultiLanguageInput, len(input.Documents)),
+		order:           make(map[string]int, len(input.Documents)),
+		chunkOrder:      make(map[string]int, len(input.Documents)),
+		chunks:          make(map[string]documentChunk),
+		stringIndexType: stringIndexType,
+	}
+	for i, doc := range input.Documents {
+		chunked.original[doc.ID] = doc
+		chunked.order[doc.ID] = i
+		starts := splitText(doc.Text, maxCharacters)
+		if len(starts) == 1 {
+			chunked.Input.Documents = append(chunked.Input.Documents, doc)
+			continue
+		}
+		for j, start := range starts {
+			end := len(doc.Text)
+			if j+1 < len(starts) {
+				end = starts[j+1]
+			}
+			chunkID := fmt.Sprintf("%s#%d", doc.ID, j)
+			chunked.chunks[chunkID] = documentChunk{originalID: doc.ID, index: j, start: start}
+			chunked.Input.Documents = append(chunked.Input.Documents, MultiLanguageInput{
+				ID:       chunkID,
+				Language: doc.Language,
+				Text:     doc.Text[start:end],
+			})
+		}
+	}
+	for i, doc := range chunked.Input.Documents {
+		chunked.chunkOrder[doc.ID] = i
+	}
+	return chunked
+}
+
+// StitchEntities merges the entities of chunks under their original document ID with offsets relative to the original text.
+func (c *ChunkedInput) StitchEntities(r *EntitiesResult) *EntitiesResult {
+	stitched := &EntitiesResult{ModelVersion: r.ModelVersion, Statistics: r.Statistics}
+	stitched.Errors = c.stitchErrors(r.Errors)
+	failed := documentIDSet(stitched.Errors)
+	byID := make(map[string]int)
+	for _, doc := range r.Documents {
+		chunk, ok := c.chunks[doc.ID]
+		if !ok {
+			stitched.Documents = append(stitched.Documents, doc)
+			continue
+		}
+		if failed[chunk.originalID] {
+			continue
+		}
+		shift := c.chunkOffset(chunk)
+		for i := range doc.Entities {
+			doc.Entities[i].Offset += shift
+		}
+		i, ok := byID[chunk.originalID]
+		if !ok {
+			byID[chunk.originalID] = len(stitched.Documents)
+			doc.ID = chunk.originalID
+			stitched.Documents = append(stitched.Documents, doc)
+			continue
+		}
+		merged := &stitched.Documents[i]
+		merged.Entities = append(merged.Entities, doc.Entities...)
+		merged.Warnings = append(merged.Warnings, doc.Warnings...)
+		merged.Statistics = mergeDocumentStatistics(merged.Statistics, doc.Statistics)
+	}
+	for i := range stitched.Documents {
+		entities := stitched.Documents[i].Entities
+		sort.SliceStable(entities, func(a, b int) bool { return entities[a].Offset < entities[b].Offset })
+	}
+	sortByInputOrder(stitched.Documents, c.order, func(d EntityRecognizedDocument) string { return d.ID })
+	sortByInputOrder(stitched.Errors, c.order, documentErrorID)
+	return stitched
+}
+
+// StitchSentiment merges the sentences of chunks under their original document ID with offsets relative to the
+// original text. The document sentiment is recomputed from the chunks: confidence scores are averaged weighted by
+// chunk length, and chunks with opposite sentiments make the document mixed.
+func (c *ChunkedInput) StitchSentiment(r *SentimentResponse) *SentimentResponse {
+	stitched := &SentimentResponse{ModelVersion: r.ModelVersion, Statistics: r.Statistics}
+	stitched.Errors = c.stitchErrors(r.Errors)
+	failed := documentIDSet(stitched.Errors)
+	type aggregate struct {
+		index     int
+		weight    float64
+		scores    SentimentConfidenceScores
+		sentiment map[Sentiment]bool
+	}
+	aggregates := make(map[string]*aggregate)
+	// Sentences are appended chunk by chunk, so chunks must be visited in text order.
+	docs := append([]SentimentAnalyzedDocument(nil), r.Documents...)
+	sortByInputOrder(docs, c.chunkOrder, func(d SentimentAnalyzedDocument) string { return d.ID })
+	for _, doc := range docs {
+		chunk, ok := c.chunks[doc.ID]
+		if !ok {
+			stitched.Documents = append(stitched.Documents, doc)
+			continue
+		}
+		if failed[chunk.originalID] {
+			continue
+		}
+		shift := c.chunkOffset(chunk)
+		for i := range doc.Sentences {
+			doc.Sentences[i].Offset += shift
+		}
+		weight := float64(utf8.RuneCountInString(c.chunkText(chunk)))
+		agg, ok := aggregates[chunk.originalID]
+		if !ok {
+			agg = &aggregate{index: len(stitched.Documents), sentiment: make(map[Sentiment]bool)}
+			aggregates[chunk.originalID] = agg
+			doc.ID = chunk.originalID
+			stitched.Documents = append(stitched.Documents, doc)
+		} else {
+			merged := &stitched.Documents[agg.index]
+			rebaseSentenceRelations(doc.Sentences, len(merged.Sentences))
+			merged.Sentences = append(merged.Sentences, doc.Sentences...)
+			merged.Warnings = append(merged.Warnings, doc.Warnings...)
+			merged.Statistics = mergeDocumentStatistics(merged.Statistics, doc.Statistics)
+		}
+		agg.weight += weight
+		agg.scores.Positive += doc.ConfidenceScores.Positive * weight
+		agg.scores.Neutral += doc.ConfidenceScores.Neutral * weight
+		agg.scores.Negative += doc.ConfidenceScores.Negative * weight
+		agg.sentiment[doc.Sentiment] = true
+	}
+	for _, agg := range aggregates {
+		merged := &stitched.Documents[agg.index]
+		if agg.weight > 0 {
+			merged.ConfidenceScores = SentimentConfidenceScores{
+				Positive: agg.scores.Positive / agg.weight,
+				Neutral:  agg.scores.Neutral / agg.weight,
+				Negative: agg.scores.Negative / agg.weight,
+			}
+		}
+		switch {
+		case agg.sentiment[SentimentMixed], agg.sentiment[SentimentPositive] && agg.sentiment[SentimentNegative]:
+			merged.Sentiment = SentimentMixed
+		case agg.sentiment[SentimentPositive]:
+			merged.Sentiment = SentimentPositive
+		case agg.sentiment[SentimentNegative]:
+			merged.Sentiment = SentimentNegative
+		default:
+			merged.Sentiment = SentimentNeutral
+		}
+	}
+	sortByInputOrder(stitched.Documents, c.order, func(d SentimentAnalyzedDocument) string { return d.ID })
+	sortByInputOrder(stitched.Errors, c.order, documentErrorID)
+	return stitched
+}
+
+// StitchExtractiveSummarization merges the summary sentences of chunks under their original document ID with
+// offsets relative to the original text. Because every chunk is summarized separately, maxSentences (if positive)
+// keeps only the highest ranked sentences of each stitched document. Sentences are returned in order of appearance.
+func (c *ChunkedInput) StitchExtractiveSummarization(r *ExtractiveSummarizationResult, maxSentences int) *ExtractiveSummarizationResult {
+	stitched := &ExtractiveSummarizationResult{ModelVersion: r.ModelVersion, Statistics: r.Statistics}
+	stitched.Errors = c.stitchErrors(r.Errors)
+	failed := documentIDSet(stitched.Errors)
+	byID := make(map[string]int)
+	for _, doc := range r.Documents {
+		chunk, ok := c.chunks[doc.ID]
+		if !ok {
+			stitched.Documents = append(stitched.Documents, doc)
+			continue
+		}
+		if failed[chunk.originalID] {
+			continue
+		}
+		shift := c.chunkOffset(chunk)
+		for i := range doc.Sentences {
+			doc.Sentences[i].Offset += shift
+		}
+		i, ok := byID[chunk.originalID]
+		if !ok {
+			byID[chunk.originalID] = len(stitched.Documents)
+			doc.ID = chunk.originalID
+			stitched.Documents = append(stitched.Documents, doc)
+			continue
+		}
+		merged := &stitched.Documents[i]
+		merged.Sentences = append(merged.Sentences, doc.Sentences...)
+		merged.Warnings = append(merged.Warnings, doc.Warnings...)
+		merged.Statistics = mergeDocumentStatistics(merged.Statistics, doc.Statistics)
+	}
+	for _, i := range byID {
+		sentences := stitched.Documents[i].Sentences
+		if maxSentences > 0 && len(sentences) > maxSentences {
+			sort.SliceStable(sentences, func(a, b int) bool { return sentences[a].RankScore > sentences[b].RankScore })
+			sentences = sentences[:maxSentences]
+		}
+		sort.SliceStable(sentences, func(a, b int) bool { return sentences[a].Offset < sentences[b].Offset })
+		stitched.Documents[i].Sentences = sentences
+	}
+	sortByInputOrder(stitched.Documents, c.order, func(d ExtractedSummaryDocumentResult) string { return d.ID })
+	sortByInputOrder(stitched.Errors, c.order, documentErrorID)
+	return stitched
+}
+
+// stitchErrors reports a chunk error as an error of its original document, keeping the first error per document.
+func (c *ChunkedInput) stitchErrors(errs []DocumentError) []DocumentError {
+	var stitched []DocumentError
+	seen := make(map[string]bool)
+	for _, docErr := range errs {
+		if chunk, ok := c.chunks[docErr.ID]; ok {
+			docErr.ID = chunk.originalID
+		}
+		if seen[docErr.ID] {
+			continue
+		}
+		seen[docErr.ID] = true
+		stitched = append(stitched, docErr)
+	}
+	return stitched
+}
+
+func (c *ChunkedInput) chunkText(chunk documentChunk) string {
+	text := c.original[chunk.originalID].Text
+	end := len(text)
+	if next, ok := c.chunks[fmt.Sprintf("%s#%d", chunk.originalID, chunk.index+1)]; ok {
+		end = next.start
+	}
+	return text[chunk.start:end]
+}
+
+// chunkOffset returns the offset of the chunk in the original text, measured in the unit of the string index type.
+func (c *ChunkedInput) chunkOffset(chunk documentChunk) int {
+	return c.stringIndexType.Length(c.original[chunk.originalID].Text[:chunk.start])
+}
+
+// rebaseSentenceRelations shifts the sentence index of opinion mining references after sentences moved by delta.
+func rebaseSentenceRelations(sentences []SentenceSentiment, delta int) {
+	for i := range sentences {
+		for j := range sentences[i].Targets {
+			relations := sentences[i].Targets[j].Relations
+			for k := range relations {
+				sentenceIndex, collection, index, err := parseSentenceRef(relations[k].Ref)
+				if err != nil {
+					continue
+				}
+				prefix := relations[k].Ref[:strings.LastIndex(relations[k].Ref, "/sentences/")]
+				relations[k].Ref = fmt.Sprintf("%s/sentences/%d/%s/%d", prefix, sentenceIndex+delta, collection, index)
+			}
+		}
+	}
+}
+
+func documentIDSet(errs []DocumentError) map[string]bool {
+	set := make(map[string]bool, len(errs))
+	for _, docErr := range errs {
+		set[docErr.ID] = true
+	}
+	return set
+}
+
+func mergeDocumentStatistics(merged *DocumentStatistics, part *DocumentStatistics) *DocumentStatistics {
+	if part == nil {
+		return merged
+	}
+	if merged == nil {
+		merged = &DocumentStatistics{}
+	}
+	merged.CharactersCount += part.CharactersCount
+	merged.TransactionsCount += part.TransactionsCount
+	return merged
+}
+
+// splitText returns the byte offsets at which text is split into chunks of at most maxCharacters code points.
+// Chunks end at the last paragraph break, then sentence end, then whitespace found in the second half of the
+// window, and otherwise at a text element boundary so that no grapheme cluster is cut.
+func splitText(text string, maxCharacters int) []int {
+	starts := []int{0}
+	start := 0
+	for {
+		end, count := start, 0
+		for end < len(text) && count < maxCharacters {
+			_, size := utf8.DecodeRuneInString(text[end:])
+			end += size
+			count++
+		}
+		if end >= len(text) {
+			return starts
+		}
+		start = findChunkEnd(text, start, end)
+		starts = append(starts, start)
+	}
+}
+
+func findChunkEnd(text string, start int, end int) int {
+	window := text[start:end]
+	minCut := len(window) / 2
+
+	if i := strings.LastIndex(window, "\n\n"); i >= minCut {
+		return start + i + 2
+	}
+	if i := strings.LastIndex(window, "\n"); i >= minCut {
+		return start + i + 1
+	}
+
+	sentenceEnd, spaceEnd := -1, -1
+	for i := 0; i < len(window); {
+		r, size := utf8.DecodeRuneInString(window[i:])
+		i += size
+		if i <= minCut {
+			continue
+		}
+		next, nextSize := utf8.DecodeRuneInString(window[i:])
+		followedBySpace := nextSize > 0 && unicode.IsSpace(next)
+		switch {
+		case r == '。' || r == '！' || r == '？' || r == '．':
+			sentenceEnd = i
+		case (r == '.' || r == '!' || r == '?' || r == '…') && followedBySpace:
+			sentenceEnd = i
+		case unicode.IsSpace(r):
+			spaceEnd = i
+		}
+	}
+	if sentenceEnd > 0 && sentenceEnd < len(window) {
+		return start + sentenceEnd
+	}
+	if spaceEnd > 0 && spaceEnd < len(window) {
+		return start + spaceEnd
+	}
+
+	// No natural boundary (e.g. long CJK runs without punctuation): cut at the last text element boundary.
+	cut := 0
+	for cut < len(window) {
+		n := textElementLen(text[start+cut:])
+		if cut+n > len(window) {
+			break
+		}
+		cut += n
+	}
+	if cut == 0 {
+		cut = textElementLen(text[start:])
+	}
+	return start + cut
+}
+
+var _ Client = (*chunkingClient)(nil)
+
+type chunkingClient struct {
+	Client
+	chunker DocumentChunker
+}
+
+// NewChunkingClient wraps c so that entity recognition and sentiment analysis split documents longer than the
+// chunker limit and stitch the results back under the original document ID. Other calls are passed through.
+// Combine it with NewBatchingClient when the chunks may exceed the number of documents allowed per request.
+// If c was created with WithStrictDocumentErrors, the stitched partial result is returned with a *DocumentErrors
+// that reports the original document IDs.
+func NewChunkingClient(c Client, chunker DocumentChunker) Client {
+	return &chunkingClient{
+		Client:  c,
+		chunker: chunker,
+	}
+}
+
+func (c *chunkingClient) AnalyzeTextEntityRecognition(ctx context.Context, input MultiLanguageAnalysisInput, parameters EntitiesTaskParameters) (*EntitiesResult, error) {
+	chunked := c.chunker.Split(input, parameters.StringIndexType)
+	result, err := c.Client.AnalyzeTextEntityRecognition(ctx, chunked.Input, parameters)
+	if !isPartialResult(result, err) {
+		return nil, err
+	}
+	stitched := chunked.StitchEntities(result)
+	return stitched, stitchedError(err, stitched.Errors)
+}
+
+func (c *chunkingClient) AnalyzeTextSentimentAnalysis(ctx context.Context, input MultiLanguageAnalysisInput, parameters SentimentAnalysisTaskParameters) (*SentimentResponse, error) {
+	chunked := c.chunker.Split(input, parameters.StringIndexType)
+	result, err := c.Client.AnalyzeTextSentimentAnalysis(ctx, chunked.Input, parameters)
+	if !isPartialResult(result, err) {
+		return nil, err
+	}
+	stitched := chunked.StitchSentiment(result)
+	return stitched, stitchedError(err, stitched.Errors)
+}
+
+// isPartialResult reports whether the wrapped client returned a result, either without error or with the
+// *DocumentErrors of strict mode.
+func isPartialResult[Result any](result *Result, err error) bool {
+	var docErrs *DocumentErrors
+	return result != nil && (err == nil || errors.As(err, &docErrs))
+}
+
+// stitchedError replaces the *DocumentErrors returned by the wrapped client, which reports chunk IDs, by one built
+// from the stitched errors.
+func stitchedError(err error, stitched []DocumentError) error {
+	if err == nil {
+		return nil
+	}
+	return &DocumentErrors{Errors: stitched}
+}
@@ -0,0 +1,164 @@
+package v20230401_test
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/kde713/azurelangai-go/textanalysis/v20230401"
+)
+
+func TestDocumentChunker_Split(t *testing.T) {
+	tests := []struct {
+		name          string
+		text          string
+		maxCharacters int
+		wantChunks    []string
+	}{
+		{
+			name:          "Short",
+			text:          "짧은 문서입니다.",
+			maxCharacters: 20,
+			wantChunks:    []string{"짧은 문서입니다."},
+		},
+		{
+			name:          "Paragraph",
+			text:          "First paragraph here.\n\nSecond paragraph.",
+			maxCharacters: 30,
+			wantChunks:    []string{"First paragraph here.\n\n", "Second paragraph."},
+		},
+		{
+			name:          "KoreanSentence",
+			text:          "오늘은 날씨가 좋습니다. 내일은 비가 올 것 같습니다. 우산을 챙기세요.",
+			maxCharacters: 20,
+			wantChunks:    []string{"오늘은 날씨가 좋습니다.", " 내일은 비가 올 것 같습니다.", " 우산을 챙기세요."},
+		},
+		{
+			name:          "CJKPunctuation",
+			text:          "今日は晴れです。明日は雨です。",
+			maxCharacters: 10,
+			wantChunks:    []string{"今日は晴れです。", "明日は雨です。"},
+		},
+		{
+			name:          "DecimalIsNotSentenceEnd",
+			text:          "Pi is 3.14159 and e is 2.71828 today",
+			maxCharacters: 20,
+			wantChunks:    []string{"Pi is 3.14159 and e ", "is 2.71828 today"},
+		},
+		{
+			name:          "EmojiSequenceIsNotCut",
+			text:          strings.Repeat("👩‍👩‍👧", 3),
+			maxCharacters: 7,
+			wantChunks:    []string{"👩‍👩‍👧", "👩‍👩‍👧", "👩‍👩‍👧"},
+		},
+		{
+			name:          "ConjoiningJamoIsNotCut",
+			text:          strings.Repeat("\u1100\u1161\u11a8", 3), // conjoining jamo of "각"
+			maxCharacters: 4,
+			wantChunks:    []string{"\u1100\u1161\u11a8", "\u1100\u1161\u11a8", "\u1100\u1161\u11a8"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chunked := v20230401.DocumentChunker{MaxCharacters: tt.maxCharacters}.Split(v20230401.MultiLanguageAnalysisInput{
+				Documents: []v20230401.MultiLanguageInput{{ID: "doc", Language: "ko", Text: tt.text}},
+			}, "")
+			var got []string
+			for _, doc := range chunked.Input.Documents {
+				if utf8.RuneCountInString(doc.Text) > tt.maxCharacters {
+					t.Errorf("Chunk %s exceeds %d characters: %q", doc.ID, tt.maxCharacters, doc.Text)
+				}
+				if doc.Language != "ko" {
+					t.Errorf("Expected chunk language to be kept, got %q", doc.Language)
+				}
+				got = append(got, doc.Text)
+			}
+			if strings.Join(got, "|") != strings.Join(tt.wantChunks, "|") {
+				t.Errorf("Expected chunks %q, got %q", tt.wantChunks, got)
+			}
+			if len(got) > 1 && chunked.Input.Documents[1].ID != "doc#1" {
+				t.Errorf("Unexpected chunk ID %s", chunked.Input.Documents[1].ID)
+			}
+		})
+	}
+}
+
+func TestChunkedInput_StitchEntities(t *testing.T) {
+	// "😀" is one text element, one code point and two UTF-16 code units.
+	text := "😀 서울에서 만나요. 😀 Seoul is nice."
+	tests := []struct {
+		stringIndexType v20230401.StringIndexType
+		wantOffset      int
+	}{
+		{stringIndexType: v20230401.StringIndexTypeTextElementV8, wantOffset: 14},
+		{stringIndexType: v20230401.StringIndexTypeUnicodeCodePoint, wantOffset: 14},
+		{stringIndexType: v20230401.StringIndexTypeUtf16CodeUnit, wantOffset: 16},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.stringIndexType), func(t *testing.T) {
+			chunked := v20230401.DocumentChunker{MaxCharacters: 20}.Split(v20230401.MultiLanguageAnalysisInput{
+				Documents: []v20230401.MultiLanguageInput{{ID: "short", Text: "Hi"}, {ID: "long", Text: text}},
+			}, tt.stringIndexType)
+			if len(chunked.Input.Documents) != 3 || chunked.Input.Documents[2].Text != " 😀 Seoul is nice." {
+				t.Fatalf("Unexpected chunks: %+v", chunked.Input.Documents)
+			}
+			chunkOffset := 3 // " 😀 " in every unit but UTF-16
+			if tt.stringIndexType == v20230401.StringIndexTypeUtf16CodeUnit {
+				chunkOffset = 4
+			}
+			result := chunked.StitchEntities(&v20230401.EntitiesResult{
+				Documents: []v20230401.EntityRecognizedDocument{
+					{ID: "long#1", Entities: []v20230401.Entity{{Text: "Seoul", Offset: chunkOffset, Length: 5}}},
+					{ID: "short"},
+					{ID: "long#0", Entities: []v20230401.Entity{{Text: "서울", Offset: 2, Length: 2}}},
+				},
+			})
+			if len(result.Documents) != 2 || result.Documents[0].ID != "short" || result.Documents[1].ID != "long" {
+				t.Fatalf("Unexpected documents: %+v", result.Documents)
+			}
+			entities := result.Documents[1].Entities
+			if len(entities) != 2 || entities[0].Offset != 2 || entities[1].Text != "Seoul" || entities[1].Offset != tt.wantOffset {
+				t.Errorf("Unexpected entities: %+v", entities)
+			}
+		})
+	}
+}
+
+func TestChunkedInput_StitchSentiment(t *testing.T) {
+	chunked := v20230401.DocumentChunker{MaxCharacters: 12}.Split(v20230401.MultiLanguageAnalysisInput{
+		Documents: []v20230401.MultiLanguageInput{{ID: "1", Text: "I love it. I hate it."}},
+	}, v20230401.StringIndexTypeUnicodeCodePoint)
+	result := chunked.StitchSentiment(&v20230401.SentimentResponse{
+		Documents: []v20230401.SentimentAnalyzedDocument{
+			{
+				ID: "1#1", Sentiment: v20230401.SentimentNegative,
+				ConfidenceScores: v20230401.SentimentConfidenceScores{Negative: 1},
+				Sentences: []v20230401.SentenceSentiment{{Text: "I hate it.", Offset: 1, Length: 10, Targets: []v20230401.SentenceTarget{{
+					Text: "it", Relations: []v20230401.TargetRelation{{RelationType: "assessment", Ref: "#/documents/1/sentences/0/assessments/0"}},
+				}}, Assessments: []v20230401.SentenceAssessment{{Text: "hate"}}}},
+			},
+			{
+				ID: "1#0", Sentiment: v20230401.SentimentPositive,
+				ConfidenceScores: v20230401.SentimentConfidenceScores{Positive: 1},
+				Sentences:        []v20230401.SentenceSentiment{{Text: "I love it.", Offset: 0, Length: 10}},
+			},
+		},
+	})
+	if len(result.Documents) != 1 {
+		t.Fatalf("Expected 1 document, got %d", len(result.Documents))
+	}
+	doc := result.Documents[0]
+	if doc.ID != "1" || doc.Sentiment != v20230401.SentimentMixed {
+		t.Errorf("Unexpected document: %s %s", doc.ID, doc.Sentiment)
+	}
+	if len(doc.Sentences) != 2 || doc.Sentences[1].Offset != 11 {
+		t.Fatalf("Unexpected sentences: %+v", doc.Sentences)
+	}
+	opinions, err := doc.MinedOpinions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(opinions) != 1 || opinions[0].Assessments[0].Text != "hate" {
+		t.Errorf("Unexpected opinions: %+v", opinions)
+	}
+}
@@ -2,10 +2,14 @@ package v20230401
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/go-resty/resty/v2"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Client interface {
@@ -13,36 +17,115 @@ type Client interface {
 	AnalyzeTextEntityRecognition(ctx context.Context, input MultiLanguageAnalysisInput, parameters EntitiesTaskParameters) (*EntitiesResult, error)
 	AnalyzeTextKeyPhraseExtraction(ctx context.Context, input MultiLanguageAnalysisInput, parameters KeyPhraseTaskParameters) (*KeyPhraseResult, error)
 	AnalyzeTextSentimentAnalysis(ctx context.Context, input MultiLanguageAnalysisInput, parameters SentimentAnalysisTaskParameters) (*SentimentResponse, error)
+	AnalyzeTextPiiEntityRecognition(ctx context.Context, input MultiLanguageAnalysisInput, parameters PiiTaskParameters) (*PiiResult, error)
+	AnalyzeTextEntityLinking(ctx context.Context, input MultiLanguageAnalysisInput, parameters EntityLinkingTaskParameters) (*EntityLinkingResult, error)
 	SubmitTextAnalyticsJob(ctx context.Context, input SubmitJobRequestBody) (string, error)
 	GetTextAnalyticsJobResult(ctx context.Context, jobID string) (*JobStatusResponse, error)
+	CancelTextAnalyticsJob(ctx context.Context, jobID string) (string, error)
 }
 
 var _ Client = (*client)(nil)
 
+const subscriptionKeyHeader = "Ocp-Apim-Subscription-Key"
+
+const defaultUserAgent = "azurelangai-go (textanalysis/" + APIVersion + ")"
+
 type client struct {
-	r *resty.Client
+	r         *resty.Client
+	rejected  func(req *resty.Request) bool
+	retry     RetryPolicy
+	limiter   *RateLimiter
+	adaptive  *AdaptiveLimiter
+	timeout   time.Duration
+	showStats bool
+	strict    bool
+
+	// Observability
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+	metrics    MetricsSink
+	logger     *requestLogger
+
+	// Job submission
+	unsafeRetry RetryPolicy
+	jobDedup    JobDedupStore
+	inflight    *inflightJobSubmissions
 }
 
 func (c client) SubmitTextAnalyticsJob(ctx context.Context, input SubmitJobRequestBody) (string, error) {
-	req, err := c.r.R().
+	ctx, cl := c.startCall(ctx, OperationSubmitJob, "", input.AnalysisInput, attrTaskCount.Int(len(input.Tasks)))
+	jobID, err := c.submitTextAnalyticsJob(ctx, input)
+	if err == nil {
+		cl.setAttributes(attrJobID.String(jobID))
+	}
+	cl.end(nil, err)
+	return jobID, err
+}
+
+func (c client) submitTextAnalyticsJob(ctx context.Context, input SubmitJobRequestBody) (string, error) {
+	key, err := JobRequestKey(input)
+	if err != nil {
+		return "", err
+	}
+	if c.jobDedup == nil {
+		return c.submitJob(ctx, key, input)
+	}
+	return c.inflight.do(ctx, key, func() (string, error) {
+		if jobID, ok, err := c.jobDedup.Load(ctx, key); err != nil {
+			return "", fmt.Errorf("failed to load job dedup record: %w", err)
+		} else if ok && jobID != "" {
+			return jobID, nil
+		} else if ok {
+			return "", &JobSubmissionInDoubtError{Key: key, Err: ErrJobSubmissionPending}
+		}
+		// The pending record keeps a submission that ends in doubt from being sent again.
+		if err := c.jobDedup.Store(ctx, key, ""); err != nil {
+			return "", fmt.Errorf("failed to store job dedup record: %w", err)
+		}
+		jobID, err := c.submitJob(ctx, key, input)
+		var inDoubtErr *JobSubmissionInDoubtError
+		switch {
+		case err == nil:
+			// The job exists at this point; failing to record it only weakens deduplication.
+			_ = c.jobDedup.Store(ctx, key, jobID)
+		case errors.As(err, &inDoubtErr):
+			if inDoubtErr.JobID != "" {
+				_ = c.jobDedup.Store(ctx, key, inDoubtErr.JobID)
+			}
+		default:
+			// The job was certainly not created.
+			_ = c.jobDedup.Delete(ctx, key)
+		}
+		return jobID, err
+	})
+}
+
+// submitJob submits the job with the unsafe retry policy and reports ambiguous failures as
+// *JobSubmissionInDoubtError.
+func (c client) submitJob(ctx context.Context, key string, input SubmitJobRequestBody) (string, error) {
+	req := c.r.R().
 		SetContext(ctx).
 		SetQueryParam("api-version", APIVersion).
 		SetBody(input).
-		SetError(ErrorResponse{}).
-		Post(SubmitJobAPIPath)
+		SetError(ErrorResponse{})
+	resp, err := c.do(req, c.unsafeRetry, resty.MethodPost, SubmitJobAPIPath)
+	if (err != nil && resp != nil && !isDialError(err)) || (err == nil && resp.StatusCode() >= http.StatusInternalServerError) {
+		inDoubtErr := &JobSubmissionInDoubtError{Key: key, Err: err}
+		if err == nil {
+			_, inDoubtErr.Err = c.handleResponse(resp, nil)
+			if jobLocation := resp.Header().Get("Operation-Location"); jobLocation != "" {
+				inDoubtErr.JobID, _ = ParseJobID(jobLocation)
+			}
+		}
+		return "", inDoubtErr
+	}
+	resp, err = c.handleResponse(resp, err)
 	if err != nil {
 		return "", err
 	}
-	if req.IsError() {
-		errorResp := req.Error().(*ErrorResponse)
-		if errorResp == nil {
-			return "", fmt.Errorf("error response parse failed: status %d", req.StatusCode())
-		}
-		return "", &TaskError{Information: errorResp.Error}
-	}
-	jobLocation := req.Header().Get("Operation-Location")
+	jobLocation := resp.Header().Get("Operation-Location")
 	if jobLocation == "" {
-		return "", fmt.Errorf("missing Operation-Location: status %d", req.StatusCode())
+		return "", fmt.Errorf("missing Operation-Location: status %d", resp.StatusCode())
 	}
 	jobID, err := ParseJobID(jobLocation)
 	if err != nil {
@@ -52,168 +135,358 @@ func (c client) SubmitTextAnalyticsJob(ctx context.Context, input SubmitJobReque
 }
 
 func (c client) GetTextAnalyticsJobResult(ctx context.Context, jobID string) (*JobStatusResponse, error) {
-	req, err := c.r.R().
+	ctx, cl := c.startCall(ctx, OperationGetJob, "", nil, attrJobID.String(jobID))
+	jobResp, err := c.getTextAnalyticsJobResult(ctx, jobID)
+	if err != nil {
+		cl.end(nil, err)
+		return nil, err
+	}
+	cl.setAttributes(attrJobStatus.String(string(jobResp.Status)))
+	if jobResp.Status.IsTerminal() {
+		err = c.checkDocumentErrors(jobResp)
+	}
+	cl.end(jobResp, err)
+	return jobResp, err
+}
+
+// checkDocumentErrors returns *DocumentErrors for the document errors of result if the client is strict.
+func (c client) checkDocumentErrors(result documentErrorLister) error {
+	if !c.strict || result == nil {
+		return nil
+	}
+	if errs := result.documentErrors(); len(errs) > 0 {
+		return &DocumentErrors{Errors: errs}
+	}
+	return nil
+}
+
+func (c client) getTextAnalyticsJobResult(ctx context.Context, jobID string) (*JobStatusResponse, error) {
+	req := c.r.R().
 		SetContext(ctx).
 		SetQueryParam("api-version", APIVersion).
+		SetQueryParams(c.statsQueryParams()).
 		SetPathParam("jobId", jobID).
-		SetResult(JobStatusResponse{}).
-		SetError(ErrorResponse{}).
-		Get(JobStatusAPIPath)
+		SetResult(JobStatusResponse{})
+	resp, err := c.execute(req, resty.MethodGet, JobStatusAPIPath)
 	if err != nil {
 		return nil, err
 	}
-	if req.IsError() {
-		errorResp := req.Error().(*ErrorResponse)
-		if errorResp == nil {
-			return nil, fmt.Errorf("error response parse failed: status %d", req.StatusCode())
-		}
-		return nil, &TaskError{Information: errorResp.Error}
-	}
-	jobResp := req.Result().(*JobStatusResponse)
+	jobResp := resp.Result().(*JobStatusResponse)
 	if jobResp == nil {
-		return nil, fmt.Errorf("job response parse failed: status %d", req.StatusCode())
+		return nil, fmt.Errorf("job response parse failed: status %d", resp.StatusCode())
 	}
 	return jobResp, nil
 }
 
-func (c client) AnalyzeTextSentimentAnalysis(ctx context.Context, input MultiLanguageAnalysisInput, parameters SentimentAnalysisTaskParameters) (*SentimentResponse, error) {
-	body := RequestBody[MultiLanguageAnalysisInput, SentimentAnalysisTaskParameters]{
-		Kind:          TaskKindSentimentAnalysis,
-		AnalysisInput: input,
-		Parameters:    parameters,
-	}
-	req, err := c.r.R().
+// CancelTextAnalyticsJob requests cancellation of a running job and returns the Operation-Location of the job.
+// The job moves to StatusCancelling and then StatusCancelled, which can be awaited with JobPoller.
+func (c client) CancelTextAnalyticsJob(ctx context.Context, jobID string) (string, error) {
+	ctx, cl := c.startCall(ctx, OperationCancelJob, "", nil, attrJobID.String(jobID))
+	jobLocation, err := c.cancelTextAnalyticsJob(ctx, jobID)
+	cl.end(nil, err)
+	return jobLocation, err
+}
+
+func (c client) cancelTextAnalyticsJob(ctx context.Context, jobID string) (string, error) {
+	req := c.r.R().
 		SetContext(ctx).
 		SetQueryParam("api-version", APIVersion).
-		SetBody(body).
-		SetResult(TaskResponse[SentimentResponse]{}).
-		SetError(ErrorResponse{}).
-		Post(AnalyzeTextAPIPath)
+		SetPathParam("jobId", jobID)
+	resp, err := c.execute(req, resty.MethodPost, JobCancelAPIPath)
 	if err != nil {
-		return nil, err
-	}
-	if req.IsError() {
-		errorResp := req.Error().(*ErrorResponse)
-		if errorResp == nil {
-			return nil, fmt.Errorf("error response parse failed: status %d", req.StatusCode())
-		}
-		return nil, &TaskError{Information: errorResp.Error}
+		return "", err
 	}
-	taskResp := req.Result().(*TaskResponse[SentimentResponse])
-	if taskResp == nil {
-		return nil, fmt.Errorf("task response parse failed: status %d", req.StatusCode())
+	jobLocation := resp.Header().Get("Operation-Location")
+	if jobLocation == "" {
+		return "", fmt.Errorf("missing Operation-Location: status %d", resp.StatusCode())
 	}
-	return &taskResp.Results, nil
+	return jobLocation, nil
+}
+
+func (c client) AnalyzeTextSentimentAnalysis(ctx context.Context, input MultiLanguageAnalysisInput, parameters SentimentAnalysisTaskParameters) (*SentimentResponse, error) {
+	return analyzeText[MultiLanguageAnalysisInput, SentimentAnalysisTaskParameters, SentimentResponse](ctx, c, TaskKindSentimentAnalysis, input, parameters)
 }
 
 func (c client) AnalyzeTextKeyPhraseExtraction(ctx context.Context, input MultiLanguageAnalysisInput, parameters KeyPhraseTaskParameters) (*KeyPhraseResult, error) {
-	body := RequestBody[MultiLanguageAnalysisInput, KeyPhraseTaskParameters]{
-		Kind:          TaskKindKeyPhraseExtraction,
+	return analyzeText[MultiLanguageAnalysisInput, KeyPhraseTaskParameters, KeyPhraseResult](ctx, c, TaskKindKeyPhraseExtraction, input, parameters)
+}
+
+func (c client) AnalyzeTextEntityRecognition(ctx context.Context, input MultiLanguageAnalysisInput, parameters EntitiesTaskParameters) (*EntitiesResult, error) {
+	return analyzeText[MultiLanguageAnalysisInput, EntitiesTaskParameters, EntitiesResult](ctx, c, TaskKindEntityRecognition, input, parameters)
+}
+
+func (c client) AnalyzeTextLanguageDetection(ctx context.Context, input LanguageDetectionAnalysisInput, parameters LanguageDetectionTaskParameters) (*LanguageDetectionResult, error) {
+	return analyzeText[LanguageDetectionAnalysisInput, LanguageDetectionTaskParameters, LanguageDetectionResult](ctx, c, TaskKindLanguageDetection, input, parameters)
+}
+
+func (c client) AnalyzeTextPiiEntityRecognition(ctx context.Context, input MultiLanguageAnalysisInput, parameters PiiTaskParameters) (*PiiResult, error) {
+	return analyzeText[MultiLanguageAnalysisInput, PiiTaskParameters, PiiResult](ctx, c, TaskKindPiiEntityRecognition, input, parameters)
+}
+
+func (c client) AnalyzeTextEntityLinking(ctx context.Context, input MultiLanguageAnalysisInput, parameters EntityLinkingTaskParameters) (*EntityLinkingResult, error) {
+	return analyzeText[MultiLanguageAnalysisInput, EntityLinkingTaskParameters, EntityLinkingResult](ctx, c, TaskKindEntityLinking, input, parameters)
+}
+
+// analyzeText runs a synchronous analyze-text task and decodes its results.
+func analyzeText[AnalysisInput any, Parameters any, Results any](ctx context.Context, c client, kind TaskKind, input AnalysisInput, parameters Parameters) (*Results, error) {
+	analysis, _ := any(input).(analysisInput)
+	ctx, cl := c.startCall(ctx, OperationAnalyzeText, kind, analysis)
+	results, err := sendAnalyzeText[AnalysisInput, Parameters, Results](ctx, c, kind, input, parameters)
+	if err != nil {
+		cl.end(nil, err)
+		return nil, err
+	}
+	lister, _ := any(results).(documentErrorLister)
+	err = c.checkDocumentErrors(lister)
+	cl.end(lister, err)
+	return results, err
+}
+
+func sendAnalyzeText[AnalysisInput any, Parameters any, Results any](ctx context.Context, c client, kind TaskKind, input AnalysisInput, parameters Parameters) (*Results, error) {
+	body := RequestBody[AnalysisInput, Parameters]{
+		Kind:          kind,
 		AnalysisInput: input,
 		Parameters:    parameters,
 	}
-	req, err := c.r.R().
+	req := c.r.R().
 		SetContext(ctx).
 		SetQueryParam("api-version", APIVersion).
+		SetQueryParams(c.statsQueryParams()).
 		SetBody(body).
-		SetResult(TaskResponse[KeyPhraseResult]{}).
-		SetError(ErrorResponse{}).
-		Post(AnalyzeTextAPIPath)
+		SetResult(TaskResponse[Results]{})
+	resp, err := c.execute(req, resty.MethodPost, AnalyzeTextAPIPath)
 	if err != nil {
 		return nil, err
 	}
-	if req.IsError() {
-		errorResp := req.Error().(*ErrorResponse)
-		if errorResp == nil {
-			return nil, fmt.Errorf("error response parse failed: status %d", req.StatusCode())
-		}
-		return nil, &TaskError{Information: errorResp.Error}
-	}
-	taskResp := req.Result().(*TaskResponse[KeyPhraseResult])
+	taskResp := resp.Result().(*TaskResponse[Results])
 	if taskResp == nil {
-		return nil, fmt.Errorf("task response parse failed: status %d", req.StatusCode())
+		return nil, fmt.Errorf("task response parse failed: status %d", resp.StatusCode())
 	}
 	return &taskResp.Results, nil
 }
 
-func (c client) AnalyzeTextEntityRecognition(ctx context.Context, input MultiLanguageAnalysisInput, parameters EntitiesTaskParameters) (*EntitiesResult, error) {
-	body := RequestBody[MultiLanguageAnalysisInput, EntitiesTaskParameters]{
-		Kind:          TaskKindEntityRecognition,
-		AnalysisInput: input,
-		Parameters:    parameters,
+// execute sends an idempotent request, retrying it according to the retry policy, and converts error responses
+// into *TaskError.
+func (c client) execute(req *resty.Request, method string, url string) (*resty.Response, error) {
+	req.SetError(ErrorResponse{})
+	return c.handleResponse(c.do(req, c.retry, method, url))
+}
+
+// do sends the request, retrying it according to policy, and returns the outcome of the last attempt.
+func (c client) do(req *resty.Request, policy RetryPolicy, method string, url string) (*resty.Response, error) {
+	start := time.Now()
+	for attempt := 1; ; attempt++ {
+		if c.limiter != nil {
+			if err := c.limiter.Wait(req.Context(), requestTextRecords(req.Body)); err != nil {
+				return nil, err
+			}
+		}
+		resp, err := c.sendLimited(req, method, url, attempt)
+		if err != nil && req.Context().Err() != nil {
+			return resp, err
+		}
+		retry, ok := policy.nextRetry(attempt, start, resp, err)
+		if !ok {
+			return resp, err
+		}
+		if policy.OnRetry != nil {
+			policy.OnRetry(retry)
+		}
+		if err := sleepContext(req.Context(), retry.Delay); err != nil {
+			return nil, err
+		}
 	}
-	req, err := c.r.R().
-		SetContext(ctx).
-		SetQueryParam("api-version", APIVersion).
-		SetBody(body).
-		SetResult(TaskResponse[EntitiesResult]{}).
-		SetError(ErrorResponse{}).
-		Post(AnalyzeTextAPIPath)
+}
+
+// sendLimited sends the request once within the adaptive concurrency limit, if any.
+func (c client) sendLimited(req *resty.Request, method string, url string, attempt int) (*resty.Response, error) {
+	if c.adaptive == nil {
+		return c.send(req, method, url, attempt)
+	}
+	release, err := c.adaptive.Acquire(req.Context())
 	if err != nil {
 		return nil, err
 	}
-	if req.IsError() {
-		errorResp := req.Error().(*ErrorResponse)
-		if errorResp == nil {
-			return nil, fmt.Errorf("error response parse failed: status %d", req.StatusCode())
-		}
-		return nil, &TaskError{Information: errorResp.Error}
+	resp, err := c.send(req, method, url, attempt)
+	switch {
+	case err != nil || resp.StatusCode() >= http.StatusInternalServerError && resp.StatusCode() != http.StatusServiceUnavailable:
+		release(AdaptiveOutcomeIgnore)
+	case resp.StatusCode() == http.StatusTooManyRequests || resp.StatusCode() == http.StatusServiceUnavailable:
+		release(AdaptiveOutcomeThrottled)
+	default:
+		release(AdaptiveOutcomeSuccess)
 	}
-	taskResp := req.Result().(*TaskResponse[EntitiesResult])
-	if taskResp == nil {
-		return nil, fmt.Errorf("task response parse failed: status %d", req.StatusCode())
+	return resp, err
+}
+
+// send sends the request once. A request rejected with 401 is sent once more if the key provider has another
+// key, or the credential another token, to offer.
+func (c client) send(req *resty.Request, method string, url string, attempt int) (*resty.Response, error) {
+	resp, err := c.executeOnce(req, method, url, attempt)
+	if err == nil && resp.StatusCode() == http.StatusUnauthorized && c.rejected != nil && c.rejected(resp.Request) {
+		resp, err = c.executeOnce(req, method, url, attempt)
 	}
-	return &taskResp.Results, nil
+	return resp, err
 }
 
-func (c client) AnalyzeTextLanguageDetection(ctx context.Context, input LanguageDetectionAnalysisInput, parameters LanguageDetectionTaskParameters) (*LanguageDetectionResult, error) {
-	body := RequestBody[LanguageDetectionAnalysisInput, LanguageDetectionTaskParameters]{
-		Kind:          TaskKindLanguageDetection,
-		AnalysisInput: input,
-		Parameters:    parameters,
+// executeOnce executes the request and reports the response to the logger, the call observer and the response
+// capture of the context, if any.
+func (c client) executeOnce(req *resty.Request, method string, url string, attempt int) (*resty.Response, error) {
+	start := time.Now()
+	resp, err := c.executeWithTimeout(req, method, url)
+	c.logger.logAttempt(req, method, url, resp, err, attempt, time.Since(start))
+	callFromContext(req.Context()).observeAttempt(resp, attempt)
+	responseCaptureFromContext(req.Context()).add(resp)
+	return resp, err
+}
+
+// executeWithTimeout executes the request within the per-request timeout, if any.
+func (c client) executeWithTimeout(req *resty.Request, method string, url string) (*resty.Response, error) {
+	c.injectTraceContext(req)
+	if c.timeout <= 0 {
+		return req.Execute(method, url)
 	}
-	req, err := c.r.R().
-		SetContext(ctx).
-		SetQueryParam("api-version", APIVersion).
-		SetBody(body).
-		SetResult(TaskResponse[LanguageDetectionResult]{}).
-		SetError(ErrorResponse{}).
-		Post(AnalyzeTextAPIPath)
+	parent := req.Context()
+	ctx, cancel := context.WithTimeout(parent, c.timeout)
+	defer cancel()
+	req.SetContext(ctx)
+	defer req.SetContext(parent)
+	resp, err := req.Execute(method, url)
+	if err != nil && parent.Err() == nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = &attemptTimeoutError{timeout: c.timeout, err: err}
+	}
+	return resp, err
+}
+
+func (c client) handleResponse(resp *resty.Response, err error) (*resty.Response, error) {
 	if err != nil {
 		return nil, err
 	}
-	if req.IsError() {
-		errorResp := req.Error().(*ErrorResponse)
+	if resp.IsError() {
+		errorResp := resp.Error().(*ErrorResponse)
 		if errorResp == nil {
-			return nil, fmt.Errorf("error response parse failed: status %d", req.StatusCode())
+			return nil, fmt.Errorf("error response parse failed: status %d", resp.StatusCode())
+		}
+		return nil, &TaskError{
+			Information: errorResp.Error,
+			StatusCode:  resp.StatusCode(),
+			RequestID:   resp.Header().Get("apim-request-id"),
 		}
-		return nil, &TaskError{Information: errorResp.Error}
 	}
-	taskResp := req.Result().(*TaskResponse[LanguageDetectionResult])
-	if taskResp == nil {
-		return nil, fmt.Errorf("task response parse failed: status %d", req.StatusCode())
+	return resp, nil
+}
+
+func (c client) statsQueryParams() map[string]string {
+	if !c.showStats {
+		return nil
 	}
-	return &taskResp.Results, nil
+	return map[string]string{"showStats": "true"}
 }
 
 func NewClient(endpoint string, key string, optAppliers ...Option) Client {
+	return NewClientWithKeyProvider(endpoint, StaticKey(key), optAppliers...)
+}
+
+// NewClientWithKeyProvider creates a client that reads the subscription key from keys before every request,
+// so keys can be rotated without recreating the client. When the service rejects a key with 401, the request
+// is retried once if keys offers another key.
+func NewClientWithKeyProvider(endpoint string, keys KeyProvider, optAppliers ...Option) Client {
+	c := newClient(endpoint, optAppliers)
+	c.rejected = func(req *resty.Request) bool {
+		return keys.Rejected(req.Context(), req.Header.Get(subscriptionKeyHeader))
+	}
+	c.r.OnBeforeRequest(func(_ *resty.Client, req *resty.Request) error {
+		key, err := keys.Key(req.Context())
+		if err != nil {
+			return fmt.Errorf("failed to get subscription key: %w", err)
+		}
+		req.SetHeader(subscriptionKeyHeader, key)
+		return nil
+	})
+	return c
+}
+
+// NewClientWithTokenCredential creates a client that authenticates with Microsoft Entra ID (Azure AD) bearer tokens
+// instead of a subscription key. The endpoint must be the custom subdomain endpoint of the resource, and the
+// identity needs the "Cognitive Services User" role on it. When the service rejects a token with 401 and credential
+// is a RejectableTokenCredential, such as the credentials of this package, the request is retried once with a new
+// token.
+func NewClientWithTokenCredential(endpoint string, credential TokenCredential, optAppliers ...Option) Client {
+	c := newClient(endpoint, optAppliers)
+	if rejectable, ok := credential.(RejectableTokenCredential); ok {
+		c.rejected = func(req *resty.Request) bool {
+			return rejectable.Rejected(req.Context(), req.Token)
+		}
+	}
+	c.r.OnBeforeRequest(func(_ *resty.Client, req *resty.Request) error {
+		token, err := credential.GetToken(req.Context(), []string{CognitiveServicesScope})
+		if err != nil {
+			return fmt.Errorf("failed to get access token: %w", err)
+		}
+		req.SetAuthToken(token.Token)
+		return nil
+	})
+	return c
+}
+
+func newClient(endpoint string, optAppliers []Option) *client {
 	o := options{}
 	for _, applier := range optAppliers {
 		applier(&o)
 	}
 
-	r := resty.New().SetBaseURL(endpoint).SetHeader("Ocp-Apim-Subscription-Key", key)
+	unsafeRetry := o.retry
+	if o.unsafeRetry != nil {
+		unsafeRetry = *o.unsafeRetry
+	}
+	unsafeRetry.throttledOnly = true
+
+	var r *resty.Client
+	if o.httpClient != nil || o.transport != nil {
+		// Copy the HTTP client, as resty sets its cookie jar and transport.
+		httpClient := &http.Client{}
+		if o.httpClient != nil {
+			copied := *o.httpClient
+			httpClient = &copied
+		}
+		if o.transport != nil {
+			httpClient.Transport = o.transport
+		}
+		r = resty.NewWithClient(httpClient)
+	} else {
+		r = resty.New()
+	}
+	userAgent := defaultUserAgent
+	if o.userAgent != "" {
+		userAgent += " " + o.userAgent
+	}
+	r = r.SetBaseURL(endpoint).
+		SetHeader("User-Agent", userAgent).
+		SetHeaders(o.headers)
 
-	// Handle retry option
-	if o.retryCount != 0 {
-		r = r.SetRetryCount(o.retryCount).SetRetryWaitTime(o.retryWaitTime).SetRetryMaxWaitTime(o.retryMaxWaitTime)
-		r = r.AddRetryCondition(func(r *resty.Response, err error) bool {
-			statusCode := r.StatusCode()
-			return statusCode == http.StatusTooManyRequests || statusCode == http.StatusInternalServerError || statusCode == http.StatusBadGateway || statusCode == http.StatusServiceUnavailable
-		})
+	var tracer trace.Tracer
+	if o.tracerProvider != nil {
+		tracer = o.tracerProvider.Tracer(tracerName)
+	}
+	propagator := o.propagator
+	if propagator == nil {
+		propagator = propagation.TraceContext{}
 	}
 
 	return &client{
-		r: r,
+		r:           r,
+		retry:       o.retry,
+		limiter:     o.limiter,
+		adaptive:    o.adaptive,
+		timeout:     o.timeout,
+		tracer:      tracer,
+		propagator:  propagator,
+		metrics:     o.metrics,
+		logger:      o.logger,
+		showStats:   o.showStats,
+		strict:      o.strict,
+		unsafeRetry: unsafeRetry,
+		jobDedup:    o.jobDedup,
+		inflight:    &inflightJobSubmissions{},
 	}
 }
@@ -160,6 +160,69 @@ func TestClient_AnalyzeTextSentimentAnalysis(t *testing.T) {
 	}
 }
 
+func TestClient_AnalyzeTextPiiEntityRecognition(t *testing.T) {
+	docID := generateUUID()
+	body := v20230401.MultiLanguageAnalysisInput{
+		Documents: []v20230401.MultiLanguageInput{
+			{
+				ID:       docID,
+				Language: "en",
+				Text:     "Call our office at 312-555-1234, or send an email to support@contoso.com.",
+			},
+		},
+	}
+	result, err := c.AnalyzeTextPiiEntityRecognition(context.TODO(), body, v20230401.PiiTaskParameters{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Errors) > 0 {
+		t.Fatal(result.Errors[0])
+	}
+	if len(result.Documents) != 1 {
+		t.Fatalf("Expected 1 document, got %d", len(result.Documents))
+	}
+	if result.Documents[0].ID != docID {
+		t.Errorf("Expected document ID %s, got %s", docID, result.Documents[0].ID)
+	}
+	if len(result.Documents[0].Entities) == 0 {
+		// At least, model must extract the phone number and the email address.
+		t.Error("[Azure Not-working] Expected at least one entity")
+	}
+	if result.Documents[0].RedactedText == body.Documents[0].Text {
+		t.Error("[Azure Not-working] Expected redacted text")
+	}
+}
+
+func TestClient_AnalyzeTextEntityLinking(t *testing.T) {
+	docID := generateUUID()
+	body := v20230401.MultiLanguageAnalysisInput{
+		Documents: []v20230401.MultiLanguageInput{
+			{
+				ID:       docID,
+				Language: "en",
+				Text:     "Microsoft was founded by Bill Gates and Paul Allen on April 4, 1975.",
+			},
+		},
+	}
+	result, err := c.AnalyzeTextEntityLinking(context.TODO(), body, v20230401.EntityLinkingTaskParameters{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Errors) > 0 {
+		t.Fatal(result.Errors[0])
+	}
+	if len(result.Documents) != 1 {
+		t.Fatalf("Expected 1 document, got %d", len(result.Documents))
+	}
+	if result.Documents[0].ID != docID {
+		t.Errorf("Expected document ID %s, got %s", docID, result.Documents[0].ID)
+	}
+	if len(result.Documents[0].Entities) == 0 {
+		// At least, model must link "Microsoft" to Wikipedia.
+		t.Error("[Azure Not-working] Expected at least one linked entity")
+	}
+}
+
 func TestClient_LongRunningOperation(t *testing.T) {
 	docID := generateUUID()
 	builder := v20230401.NewJobBuilder(
@@ -216,7 +279,7 @@ func TestClient_LongRunningOperation(t *testing.T) {
 				t.Fatalf("Unexpected result type (failed to decode): %T", task.Results)
 			}
 			if len(r.Errors) != 0 {
-				t.Fatalf("Unexpected error: %s", r.Errors[0])
+				t.Fatalf("Unexpected error: %+v", r.Errors[0])
 			}
 			if len(r.Documents) != 1 {
 				t.Fatalf("Expected 1 document, got %d", len(r.Documents))
@@ -233,7 +296,7 @@ func TestClient_LongRunningOperation(t *testing.T) {
 				t.Fatalf("Unexpected result type (failed to decode): %T", task.Results)
 			}
 			if len(r.Errors) != 0 {
-				t.Fatalf("Unexpected error: %s", r.Errors[0])
+				t.Fatalf("Unexpected error: %+v", r.Errors[0])
 			}
 			if len(r.Documents) != 1 {
 				t.Fatalf("Expected 1 document, got %d", len(r.Documents))
@@ -250,7 +313,7 @@ func TestClient_LongRunningOperation(t *testing.T) {
 				t.Fatalf("Unexpected result type (failed to decode): %T", task.Results)
 			}
 			if len(r.Errors) != 0 {
-				t.Fatalf("Unexpected error: %s", r.Errors[0])
+				t.Fatalf("Unexpected error: %+v", r.Errors[0])
 			}
 			if len(r.Documents) != 1 {
 				t.Fatalf("Expected 1 document, got %d", len(r.Documents))
@@ -267,7 +330,7 @@ func TestClient_LongRunningOperation(t *testing.T) {
 				t.Fatalf("Unexpected result type (failed to decode): %T", task.Results)
 			}
 			if len(r.Errors) != 0 {
-				t.Fatalf("Unexpected error: %s", r.Errors[0])
+				t.Fatalf("Unexpected error: %+v", r.Errors[0])
 			}
 			if len(r.Documents) != 1 {
 				t.Fatalf("Expected 1 document, got %d", len(r.Documents))
@@ -284,7 +347,7 @@ func TestClient_LongRunningOperation(t *testing.T) {
 				t.Fatalf("Unexpected result type (failed to decode): %T", task.Results)
 			}
 			if len(r.Errors) != 0 {
-				t.Fatalf("Unexpected error: %s", r.Errors[0])
+				t.Fatalf("Unexpected error: %+v", r.Errors[0])
 			}
 			if len(r.Documents) != 1 {
 				t.Fatalf("Expected 1 document, got %d", len(r.Documents))
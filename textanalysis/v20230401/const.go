@@ -4,16 +4,23 @@ const APIVersion = "2023-04-01"
 const AnalyzeTextAPIPath = "/language/:analyze-text"
 const SubmitJobAPIPath = "/language/analyze-text/jobs"
 const JobStatusAPIPath = "/language/analyze-text/jobs/{jobId}"
+const JobCancelAPIPath = "/language/analyze-text/jobs/{jobId}:cancel"
 
 type TaskKind string
 
 const (
-	TaskKindLanguageDetection        TaskKind = "LanguageDetection"
-	TaskKindEntityRecognition        TaskKind = "EntityRecognition"
-	TaskKindKeyPhraseExtraction      TaskKind = "KeyPhraseExtraction"
-	TaskKindSentimentAnalysis        TaskKind = "SentimentAnalysis"
-	TaskKindExtractiveSummarization  TaskKind = "ExtractiveSummarization"
-	TaskKindAbstractiveSummarization TaskKind = "AbstractiveSummarization"
+	TaskKindLanguageDetection               TaskKind = "LanguageDetection"
+	TaskKindEntityRecognition               TaskKind = "EntityRecognition"
+	TaskKindKeyPhraseExtraction             TaskKind = "KeyPhraseExtraction"
+	TaskKindSentimentAnalysis               TaskKind = "SentimentAnalysis"
+	TaskKindExtractiveSummarization         TaskKind = "ExtractiveSummarization"
+	TaskKindAbstractiveSummarization        TaskKind = "AbstractiveSummarization"
+	TaskKindPiiEntityRecognition            TaskKind = "PiiEntityRecognition"
+	TaskKindEntityLinking                   TaskKind = "EntityLinking"
+	TaskKindHealthcare                      TaskKind = "Healthcare"
+	TaskKindCustomEntityRecognition         TaskKind = "CustomEntityRecognition"
+	TaskKindCustomSingleLabelClassification TaskKind = "CustomSingleLabelClassification"
+	TaskKindCustomMultiLabelClassification  TaskKind = "CustomMultiLabelClassification"
 )
 
 type Sentiment string
@@ -24,3 +31,104 @@ const (
 	SentimentNegative Sentiment = "negative"
 	SentimentMixed    Sentiment = "mixed"
 )
+
+type PiiDomain string
+
+const (
+	PiiDomainNone PiiDomain = "none"
+	// PiiDomainPhi Protected Health Information, adds health-related categories to the recognized entities.
+	PiiDomainPhi PiiDomain = "phi"
+)
+
+// PiiCategory is a PII entity category. Only commonly used categories are listed; see https://aka.ms/azsdk/language/pii for the full list.
+type PiiCategory string
+
+const (
+	PiiCategoryAll                               PiiCategory = "All"
+	PiiCategoryDefault                           PiiCategory = "Default"
+	PiiCategoryPerson                            PiiCategory = "Person"
+	PiiCategoryPersonType                        PiiCategory = "PersonType"
+	PiiCategoryPhoneNumber                       PiiCategory = "PhoneNumber"
+	PiiCategoryOrganization                      PiiCategory = "Organization"
+	PiiCategoryAddress                           PiiCategory = "Address"
+	PiiCategoryEmail                             PiiCategory = "Email"
+	PiiCategoryURL                               PiiCategory = "URL"
+	PiiCategoryIPAddress                         PiiCategory = "IPAddress"
+	PiiCategoryDateTime                          PiiCategory = "DateTime"
+	PiiCategoryDate                              PiiCategory = "Date"
+	PiiCategoryAge                               PiiCategory = "Age"
+	PiiCategoryCreditCardNumber                  PiiCategory = "CreditCardNumber"
+	PiiCategoryInternationalBankingAccountNumber PiiCategory = "InternationalBankingAccountNumber"
+	PiiCategorySWIFTCode                         PiiCategory = "SWIFTCode"
+	PiiCategoryUSSocialSecurityNumber            PiiCategory = "USSocialSecurityNumber"
+	PiiCategoryKRResidentRegistrationNumber      PiiCategory = "KRResidentRegistrationNumber"
+)
+
+// FhirVersion401 is the only FHIR version supported by the Healthcare task.
+const FhirVersion401 = "4.0.1"
+
+type HealthcareDocumentType string
+
+const (
+	HealthcareDocumentTypeNone               HealthcareDocumentType = "None"
+	HealthcareDocumentTypeClinicalTrial      HealthcareDocumentType = "ClinicalTrial"
+	HealthcareDocumentTypeDischargeSummary   HealthcareDocumentType = "DischargeSummary"
+	HealthcareDocumentTypeProgressNote       HealthcareDocumentType = "ProgressNote"
+	HealthcareDocumentTypeHistoryAndPhysical HealthcareDocumentType = "HistoryAndPhysical"
+	HealthcareDocumentTypeConsult            HealthcareDocumentType = "Consult"
+	HealthcareDocumentTypeImaging            HealthcareDocumentType = "Imaging"
+	HealthcareDocumentTypePathology          HealthcareDocumentType = "Pathology"
+	HealthcareDocumentTypeProcedureNote      HealthcareDocumentType = "ProcedureNote"
+)
+
+// ErrorCode is the code of a service error.
+type ErrorCode string
+
+const (
+	ErrorCodeInvalidRequest                        ErrorCode = "InvalidRequest"
+	ErrorCodeInvalidArgument                       ErrorCode = "InvalidArgument"
+	ErrorCodeUnauthorized                          ErrorCode = "Unauthorized"
+	ErrorCodeForbidden                             ErrorCode = "Forbidden"
+	ErrorCodeNotFound                              ErrorCode = "NotFound"
+	ErrorCodeProjectNotFound                       ErrorCode = "ProjectNotFound"
+	ErrorCodeOperationNotFound                     ErrorCode = "OperationNotFound"
+	ErrorCodeAzureCognitiveSearchNotFound          ErrorCode = "AzureCognitiveSearchNotFound"
+	ErrorCodeAzureCognitiveSearchIndexNotFound     ErrorCode = "AzureCognitiveSearchIndexNotFound"
+	ErrorCodeTooManyRequests                       ErrorCode = "TooManyRequests"
+	ErrorCodeAzureCognitiveSearchThrottling        ErrorCode = "AzureCognitiveSearchThrottling"
+	ErrorCodeAzureCognitiveSearchIndexLimitReached ErrorCode = "AzureCognitiveSearchIndexLimitReached"
+	ErrorCodeInternalServerError                   ErrorCode = "InternalServerError"
+	ErrorCodeServiceUnavailable                    ErrorCode = "ServiceUnavailable"
+	ErrorCodeTimeout                               ErrorCode = "Timeout"
+	ErrorCodeQuotaExceeded                         ErrorCode = "QuotaExceeded"
+	ErrorCodeConflict                              ErrorCode = "Conflict"
+	ErrorCodeWarning                               ErrorCode = "Warning"
+)
+
+// InnerErrorCode is the code of the inner error that details a service error.
+type InnerErrorCode string
+
+const (
+	InnerErrorCodeInvalidRequest                 InnerErrorCode = "InvalidRequest"
+	InnerErrorCodeInvalidParameterValue          InnerErrorCode = "InvalidParameterValue"
+	InnerErrorCodeKnowledgeBaseNotFound          InnerErrorCode = "KnowledgeBaseNotFound"
+	InnerErrorCodeAzureCognitiveSearchNotFound   InnerErrorCode = "AzureCognitiveSearchNotFound"
+	InnerErrorCodeAzureCognitiveSearchThrottling InnerErrorCode = "AzureCognitiveSearchThrottling"
+	InnerErrorCodeExtractionFailure              InnerErrorCode = "ExtractionFailure"
+	InnerErrorCodeInvalidRequestBodyFormat       InnerErrorCode = "InvalidRequestBodyFormat"
+	InnerErrorCodeEmptyRequest                   InnerErrorCode = "EmptyRequest"
+	InnerErrorCodeMissingInputDocuments          InnerErrorCode = "MissingInputDocuments"
+	InnerErrorCodeInvalidDocument                InnerErrorCode = "InvalidDocument"
+	InnerErrorCodeModelVersionIncorrect          InnerErrorCode = "ModelVersionIncorrect"
+	InnerErrorCodeInvalidDocumentBatch           InnerErrorCode = "InvalidDocumentBatch"
+	InnerErrorCodeUnsupportedLanguageCode        InnerErrorCode = "UnsupportedLanguageCode"
+	InnerErrorCodeInvalidCountryHint             InnerErrorCode = "InvalidCountryHint"
+)
+
+// WarningCode is the code of a document warning.
+type WarningCode string
+
+const (
+	WarningCodeLongWordsInDocument WarningCode = "LongWordsInDocument"
+	WarningCodeDocumentTruncated   WarningCode = "DocumentTruncated"
+)
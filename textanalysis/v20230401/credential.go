@@ -0,0 +1,359 @@
+package v20230401
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// CognitiveServicesScope is the OAuth scope of Azure AI services (Language) resources.
+const CognitiveServicesScope = "https://cognitiveservices.azure.com/.default"
+
+const (
+	defaultAuthorityHost = "https://login.microsoftonline.com"
+	defaultIMDSEndpoint  = "http://169.254.169.254/metadata/identity/oauth2/token"
+	imdsAPIVersion       = "2018-02-01"
+	// tokenFetchTimeout bounds a shared token request, which outlives the caller that started it.
+	tokenFetchTimeout = time.Minute
+)
+
+// AccessToken is a bearer token with its expiration time.
+type AccessToken struct {
+	Token     string
+	ExpiresOn time.Time
+}
+
+// TokenCredential fetches Microsoft Entra ID (Azure AD) access tokens. Implementations must be safe for concurrent use.
+type TokenCredential interface {
+	GetToken(ctx context.Context, scopes []string) (AccessToken, error)
+}
+
+// RejectableTokenCredential is a TokenCredential that can drop a token the service rejected, e.g. because it was
+// revoked. The credentials of this package implement it.
+type RejectableTokenCredential interface {
+	TokenCredential
+	// Rejected is called when the service rejected token with 401 Unauthorized. It reports whether GetToken will
+	// now return another token, in which case the request is retried once.
+	Rejected(ctx context.Context, token string) bool
+}
+
+// AuthenticationError is returned when the token endpoint rejects a token request.
+type AuthenticationError struct {
+	StatusCode  int
+	Code        string
+	Description string
+}
+
+func (e *AuthenticationError) Error() string {
+	return fmt.Sprintf("authentication failed: status %d: %s: %s", e.StatusCode, e.Code, e.Description)
+}
+
+type credentialOptions struct {
+	authorityHost string
+	imdsEndpoint  string
+	httpClient    *http.Client
+	refreshMargin time.Duration
+}
+
+type CredentialOption func(*credentialOptions)
+
+// WithAuthorityHost overrides the Microsoft Entra ID authority host, e.g. for sovereign clouds. Defaults to
+// AZURE_AUTHORITY_HOST or https://login.microsoftonline.com.
+func WithAuthorityHost(authorityHost string) CredentialOption {
+	return func(o *credentialOptions) {
+		o.authorityHost = authorityHost
+	}
+}
+
+// WithIMDSEndpoint overrides the token endpoint of the Azure Instance Metadata Service used by managed identities.
+func WithIMDSEndpoint(endpoint string) CredentialOption {
+	return func(o *credentialOptions) {
+		o.imdsEndpoint = endpoint
+	}
+}
+
+// WithCredentialHTTPClient sets the HTTP client used for token requests.
+func WithCredentialHTTPClient(httpClient *http.Client) CredentialOption {
+	return func(o *credentialOptions) {
+		o.httpClient = httpClient
+	}
+}
+
+// WithTokenRefreshMargin sets how long before expiry a cached token is refreshed. Defaults to 5 minutes.
+func WithTokenRefreshMargin(margin time.Duration) CredentialOption {
+	return func(o *credentialOptions) {
+		o.refreshMargin = margin
+	}
+}
+
+func newCredentialOptions(optAppliers []CredentialOption) credentialOptions {
+	o := credentialOptions{
+		authorityHost: os.Getenv("AZURE_AUTHORITY_HOST"),
+		imdsEndpoint:  defaultIMDSEndpoint,
+		refreshMargin: 5 * time.Minute,
+	}
+	if o.authorityHost == "" {
+		o.authorityHost = defaultAuthorityHost
+	}
+	for _, applier := range optAppliers {
+		applier(&o)
+	}
+	return o
+}
+
+func (o credentialOptions) restyClient() *resty.Client {
+	if o.httpClient != nil {
+		return resty.NewWithClient(o.httpClient)
+	}
+	return resty.New()
+}
+
+// NewClientSecretCredential authenticates a service principal with a client secret.
+// The returned credential caches tokens and refreshes them before they expire.
+func NewClientSecretCredential(tenantID string, clientID string, clientSecret string, optAppliers ...CredentialOption) TokenCredential {
+	o := newCredentialOptions(optAppliers)
+	return NewCachedTokenCredential(&entraCredential{
+		r:        o.restyClient(),
+		tokenURL: entraTokenURL(o.authorityHost, tenantID),
+		clientID: clientID,
+		formData: func() (map[string]string, error) {
+			return map[string]string{"client_secret": clientSecret}, nil
+		},
+	}, o.refreshMargin)
+}
+
+// NewWorkloadIdentityCredential authenticates with workload identity federation (e.g. Azure Kubernetes Service),
+// exchanging the token in tokenFilePath for an access token. The file is read on every token request so that
+// rotated tokens are picked up. Empty arguments default to AZURE_TENANT_ID, AZURE_CLIENT_ID and
+// AZURE_FEDERATED_TOKEN_FILE. The returned credential caches tokens and refreshes them before they expire.
+func NewWorkloadIdentityCredential(tenantID string, clientID string, tokenFilePath string, optAppliers ...CredentialOption) TokenCredential {
+	if tenantID == "" {
+		tenantID = os.Getenv("AZURE_TENANT_ID")
+	}
+	if clientID == "" {
+		clientID = os.Getenv("AZURE_CLIENT_ID")
+	}
+	if tokenFilePath == "" {
+		tokenFilePath = os.Getenv("AZURE_FEDERATED_TOKEN_FILE")
+	}
+	o := newCredentialOptions(optAppliers)
+	return NewCachedTokenCredential(&entraCredential{
+		r:        o.restyClient(),
+		tokenURL: entraTokenURL(o.authorityHost, tenantID),
+		clientID: clientID,
+		formData: func() (map[string]string, error) {
+			assertion, err := os.ReadFile(tokenFilePath)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read federated token: %w", err)
+			}
+			return map[string]string{
+				"client_assertion_type": "urn:ietf:params:oauth:client-assertion-type:jwt-bearer",
+				"client_assertion":      strings.TrimSpace(string(assertion)),
+			}, nil
+		},
+	}, o.refreshMargin)
+}
+
+// NewManagedIdentityCredential authenticates with the managed identity of the Azure host through the Instance
+// Metadata Service (IMDS). clientID selects a user-assigned identity; leave it empty for the system-assigned one.
+// The returned credential caches tokens and refreshes them before they expire.
+func NewManagedIdentityCredential(clientID string, optAppliers ...CredentialOption) TokenCredential {
+	o := newCredentialOptions(optAppliers)
+	return NewCachedTokenCredential(&managedIdentityCredential{
+		r:        o.restyClient(),
+		endpoint: o.imdsEndpoint,
+		clientID: clientID,
+	}, o.refreshMargin)
+}
+
+func entraTokenURL(authorityHost string, tenantID string) string {
+	return fmt.Sprintf("%s/%s/oauth2/v2.0/token", strings.TrimSuffix(authorityHost, "/"), tenantID)
+}
+
+// tokenResponse is the token payload of both Microsoft Entra ID and IMDS. IMDS encodes numbers as strings.
+type tokenResponse struct {
+	AccessToken      string      `json:"access_token"`
+	ExpiresIn        json.Number `json:"expires_in"`
+	ExpiresOn        json.Number `json:"expires_on"`
+	Error            string      `json:"error"`
+	ErrorDescription string      `json:"error_description"`
+}
+
+func (t tokenResponse) accessToken(now time.Time) (AccessToken, error) {
+	if t.AccessToken == "" {
+		return AccessToken{}, fmt.Errorf("token response has no access_token")
+	}
+	if expiresOn, err := strconv.ParseInt(t.ExpiresOn.String(), 10, 64); err == nil {
+		return AccessToken{Token: t.AccessToken, ExpiresOn: time.Unix(expiresOn, 0)}, nil
+	}
+	expiresIn, err := strconv.ParseInt(t.ExpiresIn.String(), 10, 64)
+	if err != nil {
+		return AccessToken{}, fmt.Errorf("token response has no valid expiry: %w", err)
+	}
+	return AccessToken{Token: t.AccessToken, ExpiresOn: now.Add(time.Duration(expiresIn) * time.Second)}, nil
+}
+
+func parseTokenResponse(resp *resty.Response) (AccessToken, error) {
+	var body tokenResponse
+	decodeErr := json.Unmarshal(resp.Body(), &body)
+	if resp.IsError() {
+		return AccessToken{}, &AuthenticationError{StatusCode: resp.StatusCode(), Code: body.Error, Description: body.ErrorDescription}
+	}
+	if decodeErr != nil {
+		return AccessToken{}, fmt.Errorf("token response parse failed: %w", decodeErr)
+	}
+	return body.accessToken(time.Now())
+}
+
+// entraCredential requests tokens with the OAuth 2.0 client credentials flow.
+type entraCredential struct {
+	r        *resty.Client
+	tokenURL string
+	clientID string
+	formData func() (map[string]string, error)
+}
+
+func (c *entraCredential) GetToken(ctx context.Context, scopes []string) (AccessToken, error) {
+	form, err := c.formData()
+	if err != nil {
+		return AccessToken{}, err
+	}
+	form["grant_type"] = "client_credentials"
+	form["client_id"] = c.clientID
+	form["scope"] = strings.Join(scopes, " ")
+	resp, err := c.r.R().
+		SetContext(ctx).
+		SetFormData(form).
+		Post(c.tokenURL)
+	if err != nil {
+		return AccessToken{}, err
+	}
+	return parseTokenResponse(resp)
+}
+
+type managedIdentityCredential struct {
+	r        *resty.Client
+	endpoint string
+	clientID string
+}
+
+func (c *managedIdentityCredential) GetToken(ctx context.Context, scopes []string) (AccessToken, error) {
+	if len(scopes) != 1 {
+		return AccessToken{}, fmt.Errorf("managed identity requires exactly one scope, got %d", len(scopes))
+	}
+	req := c.r.R().
+		SetContext(ctx).
+		SetHeader("Metadata", "true").
+		SetQueryParam("api-version", imdsAPIVersion).
+		SetQueryParam("resource", strings.TrimSuffix(scopes[0], "/.default"))
+	if c.clientID != "" {
+		req = req.SetQueryParam("client_id", c.clientID)
+	}
+	resp, err := req.Get(c.endpoint)
+	if err != nil {
+		return AccessToken{}, err
+	}
+	return parseTokenResponse(resp)
+}
+
+type cachedTokenCredential struct {
+	credential    TokenCredential
+	refreshMargin time.Duration
+
+	mu      sync.Mutex
+	tokens  map[string]AccessToken
+	fetches map[string]*tokenFetch
+}
+
+// tokenFetch is a token request shared by the callers of a scope set.
+type tokenFetch struct {
+	done  chan struct{}
+	token AccessToken
+	err   error
+}
+
+// NewCachedTokenCredential caches the tokens of credential per scope set and fetches a new token once the cached
+// one expires within refreshMargin or is rejected by the service. Concurrent callers share a single token request,
+// which isn't canceled when the caller that started it gives up but fails after a minute.
+func NewCachedTokenCredential(credential TokenCredential, refreshMargin time.Duration) RejectableTokenCredential {
+	return &cachedTokenCredential{
+		credential:    credential,
+		refreshMargin: refreshMargin,
+		tokens:        make(map[string]AccessToken),
+		fetches:       make(map[string]*tokenFetch),
+	}
+}
+
+func (c *cachedTokenCredential) GetToken(ctx context.Context, scopes []string) (AccessToken, error) {
+	key := strings.Join(scopes, " ")
+	c.mu.Lock()
+	if token, ok := c.tokens[key]; ok && time.Until(token.ExpiresOn) > c.refreshMargin {
+		c.mu.Unlock()
+		return token, nil
+	}
+	fetch, ok := c.fetches[key]
+	if !ok {
+		fetch = &tokenFetch{done: make(chan struct{})}
+		c.fetches[key] = fetch
+		go c.fetch(detachedContext{ctx}, key, scopes, fetch)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return AccessToken{}, ctx.Err()
+	case <-fetch.done:
+		return fetch.token, fetch.err
+	}
+}
+
+// Rejected drops token from the cache, so that the next GetToken fetches a new one.
+func (c *cachedTokenCredential) Rejected(_ context.Context, token string) bool {
+	if token == "" {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, cached := range c.tokens {
+		if cached.Token == token {
+			delete(c.tokens, key)
+		}
+	}
+	return true
+}
+
+func (c *cachedTokenCredential) fetch(ctx context.Context, key string, scopes []string, fetch *tokenFetch) {
+	ctx, cancel := context.WithTimeout(ctx, tokenFetchTimeout)
+	defer cancel()
+	token, err := c.credential.GetToken(ctx, scopes)
+	c.mu.Lock()
+	delete(c.fetches, key)
+	if err == nil {
+		c.tokens[key] = token
+	} else if cached, ok := c.tokens[key]; ok && time.Now().Before(cached.ExpiresOn) {
+		// Keep using the cached token until it actually expires.
+		token, err = cached, nil
+	}
+	fetch.token, fetch.err = token, err
+	c.mu.Unlock()
+	close(fetch.done)
+}
+
+// detachedContext keeps the values of a context but not its cancellation.
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+
+func (detachedContext) Done() <-chan struct{} { return nil }
+
+func (detachedContext) Err() error { return nil }
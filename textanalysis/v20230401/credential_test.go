@@ -0,0 +1,232 @@
+package v20230401_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kde713/azurelangai-go/textanalysis/v20230401"
+)
+
+// newFakeTokenServer emulates the Microsoft Entra ID token endpoint of tenant "tenant-1" and the IMDS endpoint.
+func newFakeTokenServer(t *testing.T, expiresIn int) (*httptest.Server, *int32) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&requests, 1)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/tenant-1/oauth2/v2.0/token":
+			if err := r.ParseForm(); err != nil {
+				t.Fatal(err)
+			}
+			if r.PostForm.Get("grant_type") != "client_credentials" || r.PostForm.Get("client_id") != "client-1" || r.PostForm.Get("scope") != v20230401.CognitiveServicesScope {
+				t.Errorf("Unexpected token request: %v", r.PostForm)
+			}
+			secret, assertion := r.PostForm.Get("client_secret"), r.PostForm.Get("client_assertion")
+			if secret != "secret-1" && assertion != "federated-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				_, _ = w.Write([]byte(`{"error":"invalid_client","error_description":"AADSTS7000215: Invalid client secret provided."}`))
+				return
+			}
+			_, _ = fmt.Fprintf(w, `{"token_type":"Bearer","expires_in":%d,"access_token":"token-%d"}`, expiresIn, n)
+		case "/metadata/identity/oauth2/token":
+			if r.Header.Get("Metadata") != "true" || r.URL.Query().Get("resource") != "https://cognitiveservices.azure.com" {
+				t.Errorf("Unexpected IMDS request: %v %v", r.Header, r.URL.Query())
+			}
+			_, _ = fmt.Fprintf(w, `{"access_token":"mi-token-%s","expires_in":"%d","expires_on":"%d","token_type":"Bearer"}`,
+				r.URL.Query().Get("client_id"), expiresIn, time.Now().Add(time.Duration(expiresIn)*time.Second).Unix())
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+func TestNewClientSecretCredential(t *testing.T) {
+	srv, requests := newFakeTokenServer(t, 3600)
+	cred := v20230401.NewClientSecretCredential("tenant-1", "client-1", "secret-1", v20230401.WithAuthorityHost(srv.URL))
+
+	for i := 0; i < 3; i++ {
+		token, err := cred.GetToken(context.TODO(), []string{v20230401.CognitiveServicesScope})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if token.Token != "token-1" {
+			t.Errorf("Expected cached token-1, got %s", token.Token)
+		}
+		if time.Until(token.ExpiresOn) < 59*time.Minute {
+			t.Errorf("Unexpected expiry %s", token.ExpiresOn)
+		}
+	}
+	if got := atomic.LoadInt32(requests); got != 1 {
+		t.Errorf("Expected 1 token request, got %d", got)
+	}
+}
+
+func TestNewClientSecretCredential_RefreshBeforeExpiry(t *testing.T) {
+	srv, requests := newFakeTokenServer(t, 60)
+	cred := v20230401.NewClientSecretCredential("tenant-1", "client-1", "secret-1",
+		v20230401.WithAuthorityHost(srv.URL), v20230401.WithTokenRefreshMargin(2*time.Minute))
+
+	for i := 1; i <= 2; i++ {
+		token, err := cred.GetToken(context.TODO(), []string{v20230401.CognitiveServicesScope})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := fmt.Sprintf("token-%d", i); token.Token != want {
+			t.Errorf("Expected %s, got %s", want, token.Token)
+		}
+	}
+	if got := atomic.LoadInt32(requests); got != 2 {
+		t.Errorf("Expected 2 token requests, got %d", got)
+	}
+}
+
+func TestNewClientSecretCredential_InvalidSecret(t *testing.T) {
+	srv, _ := newFakeTokenServer(t, 3600)
+	cred := v20230401.NewClientSecretCredential("tenant-1", "client-1", "wrong", v20230401.WithAuthorityHost(srv.URL))
+
+	_, err := cred.GetToken(context.TODO(), []string{v20230401.CognitiveServicesScope})
+	var authErr *v20230401.AuthenticationError
+	if !errors.As(err, &authErr) {
+		t.Fatalf("Expected AuthenticationError, got %v", err)
+	}
+	if authErr.StatusCode != http.StatusUnauthorized || authErr.Code != "invalid_client" {
+		t.Errorf("Unexpected error: %+v", authErr)
+	}
+}
+
+func TestNewWorkloadIdentityCredential(t *testing.T) {
+	srv, _ := newFakeTokenServer(t, 3600)
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("federated-token\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cred := v20230401.NewWorkloadIdentityCredential("tenant-1", "client-1", tokenFile, v20230401.WithAuthorityHost(srv.URL))
+
+	token, err := cred.GetToken(context.TODO(), []string{v20230401.CognitiveServicesScope})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.Token != "token-1" {
+		t.Errorf("Unexpected token %s", token.Token)
+	}
+}
+
+func TestNewManagedIdentityCredential(t *testing.T) {
+	srv, _ := newFakeTokenServer(t, 3600)
+	cred := v20230401.NewManagedIdentityCredential("user-assigned", v20230401.WithIMDSEndpoint(srv.URL+"/metadata/identity/oauth2/token"))
+
+	token, err := cred.GetToken(context.TODO(), []string{v20230401.CognitiveServicesScope})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.Token != "mi-token-user-assigned" {
+		t.Errorf("Unexpected token %s", token.Token)
+	}
+	if time.Until(token.ExpiresOn) < 59*time.Minute {
+		t.Errorf("Unexpected expiry %s", token.ExpiresOn)
+	}
+}
+
+func TestNewClientWithTokenCredential(t *testing.T) {
+	tokenSrv, _ := newFakeTokenServer(t, 3600)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer token-1" {
+			t.Errorf("Unexpected Authorization header %q", got)
+		}
+		if got := r.Header.Get("Ocp-Apim-Subscription-Key"); got != "" {
+			t.Errorf("Unexpected subscription key %q", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jobId":"job-1","status":"running","tasks":{"items":[]}}`))
+	}))
+	defer srv.Close()
+
+	cred := v20230401.NewClientSecretCredential("tenant-1", "client-1", "secret-1", v20230401.WithAuthorityHost(tokenSrv.URL))
+	client := v20230401.NewClientWithTokenCredential(srv.URL, cred)
+	if _, err := client.GetTextAnalyticsJobResult(context.TODO(), "job-1"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewClientWithTokenCredential_RejectedToken(t *testing.T) {
+	tokenSrv, requests := newFakeTokenServer(t, 3600)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Header.Get("Authorization") == "Bearer token-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":{"code":"401","message":"Access denied due to invalid token."}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"jobId":"job-1","status":"running","tasks":{"items":[]}}`))
+	}))
+	defer srv.Close()
+
+	cred := v20230401.NewClientSecretCredential("tenant-1", "client-1", "secret-1", v20230401.WithAuthorityHost(tokenSrv.URL))
+	client := v20230401.NewClientWithTokenCredential(srv.URL, cred)
+	if _, err := client.GetTextAnalyticsJobResult(context.TODO(), "job-1"); err != nil {
+		t.Fatalf("Expected the request to be retried with a new token, got %v", err)
+	}
+	if got := atomic.LoadInt32(requests); got != 2 {
+		t.Errorf("Expected 2 token requests, got %d", got)
+	}
+}
+
+// blockingCredential returns a token for the scope "fast" at once and blocks other scopes until release is closed.
+type blockingCredential struct {
+	release  chan struct{}
+	requests int32
+}
+
+func (c *blockingCredential) GetToken(ctx context.Context, scopes []string) (v20230401.AccessToken, error) {
+	n := atomic.AddInt32(&c.requests, 1)
+	if scopes[0] != "fast" {
+		<-c.release
+	}
+	return v20230401.AccessToken{Token: fmt.Sprintf("token-%d", n), ExpiresOn: time.Now().Add(time.Hour)}, nil
+}
+
+func TestNewCachedTokenCredential_SlowFetch(t *testing.T) {
+	inner := &blockingCredential{release: make(chan struct{})}
+	cred := v20230401.NewCachedTokenCredential(inner, time.Minute)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := cred.GetToken(ctx, []string{"slow"}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context.DeadlineExceeded, got %v", err)
+	}
+
+	if _, err := cred.GetToken(context.Background(), []string{"fast"}); err != nil {
+		t.Fatalf("Expected other scopes not to wait for the slow fetch, got %v", err)
+	}
+
+	results := make(chan string, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			token, err := cred.GetToken(context.Background(), []string{"slow"})
+			if err != nil {
+				t.Error(err)
+			}
+			results <- token.Token
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(inner.release)
+	for i := 0; i < 2; i++ {
+		if token := <-results; token != "token-1" {
+			t.Errorf("Expected the shared token-1, got %s", token)
+		}
+	}
+	if got := atomic.LoadInt32(&inner.requests); got != 2 {
+		t.Errorf("Expected 2 token requests, got %d", got)
+	}
+}
@@ -1,11 +1,218 @@
 package v20230401
 
-import "fmt"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Sentinel errors matched by *TaskError with errors.Is, by error code or, if the code is unknown, by HTTP status.
+var (
+	ErrInvalidRequest      = errors.New("invalid request")
+	ErrInvalidArgument     = errors.New("invalid argument")
+	ErrUnauthorized        = errors.New("unauthorized")
+	ErrForbidden           = errors.New("forbidden")
+	ErrNotFound            = errors.New("not found")
+	ErrProjectNotFound     = errors.New("project not found")
+	ErrTooManyRequests     = errors.New("too many requests")
+	ErrQuotaExceeded       = errors.New("quota exceeded")
+	ErrConflict            = errors.New("conflict")
+	ErrInternalServerError = errors.New("internal server error")
+	ErrServiceUnavailable  = errors.New("service unavailable")
+	ErrTimeout             = errors.New("timeout")
+)
+
+// errorCodeSentinels maps error codes to the sentinels they match. Codes of more specific sentinels also match the
+// general one, e.g. ProjectNotFound matches ErrNotFound.
+var errorCodeSentinels = map[ErrorCode][]error{
+	ErrorCodeInvalidRequest:                        {ErrInvalidRequest},
+	ErrorCodeInvalidArgument:                       {ErrInvalidArgument},
+	ErrorCodeUnauthorized:                          {ErrUnauthorized},
+	ErrorCodeForbidden:                             {ErrForbidden},
+	ErrorCodeNotFound:                              {ErrNotFound},
+	ErrorCodeProjectNotFound:                       {ErrProjectNotFound, ErrNotFound},
+	ErrorCodeOperationNotFound:                     {ErrNotFound},
+	ErrorCodeAzureCognitiveSearchNotFound:          {ErrNotFound},
+	ErrorCodeAzureCognitiveSearchIndexNotFound:     {ErrNotFound},
+	ErrorCodeTooManyRequests:                       {ErrTooManyRequests},
+	ErrorCodeAzureCognitiveSearchThrottling:        {ErrTooManyRequests},
+	ErrorCodeAzureCognitiveSearchIndexLimitReached: {ErrQuotaExceeded},
+	ErrorCodeQuotaExceeded:                         {ErrQuotaExceeded},
+	ErrorCodeConflict:                              {ErrConflict},
+	ErrorCodeInternalServerError:                   {ErrInternalServerError},
+	ErrorCodeServiceUnavailable:                    {ErrServiceUnavailable},
+	ErrorCodeTimeout:                               {ErrTimeout},
+}
+
+// statusSentinels maps HTTP statuses to the sentinels matched by errors without a known code.
+var statusSentinels = map[int]error{
+	http.StatusBadRequest:          ErrInvalidRequest,
+	http.StatusUnauthorized:        ErrUnauthorized,
+	http.StatusForbidden:           ErrForbidden,
+	http.StatusNotFound:            ErrNotFound,
+	http.StatusConflict:            ErrConflict,
+	http.StatusTooManyRequests:     ErrTooManyRequests,
+	http.StatusInternalServerError: ErrInternalServerError,
+	http.StatusServiceUnavailable:  ErrServiceUnavailable,
+	http.StatusGatewayTimeout:      ErrTimeout,
+}
 
 type TaskError struct {
 	Information ErrorInformation
+	// StatusCode HTTP status of the error response.
+	StatusCode int
+	// RequestID Value of the apim-request-id header of the error response, which Azure support asks for.
+	RequestID string
 }
 
 func (e *TaskError) Error() string {
-	return fmt.Sprintf("task failed: %s", e.Information.Message)
+	var b strings.Builder
+	b.WriteString("task failed: ")
+	e.Information.writeTo(&b)
+	if e.RequestID != "" {
+		fmt.Fprintf(&b, " (status %d, apim-request-id %s)", e.StatusCode, e.RequestID)
+	}
+	return b.String()
+}
+
+// Is reports whether target is a sentinel error matching the error code, or the HTTP status if the code is not a
+// known ErrorCode, e.g. the numeric codes of API Management gateway errors such as "401".
+func (e *TaskError) Is(target error) bool {
+	if sentinels, ok := errorCodeSentinels[e.Information.Code]; ok {
+		for _, sentinel := range sentinels {
+			if sentinel == target {
+				return true
+			}
+		}
+		return false
+	}
+	sentinel, ok := statusSentinels[e.StatusCode]
+	return ok && sentinel == target
+}
+
+// writeTo writes the message prefixed with the code and the innermost inner error code, if any.
+func (i ErrorInformation) writeTo(b *strings.Builder) {
+	if i.Code != "" {
+		b.WriteString(string(i.Code))
+		if inner := i.innermostError(); inner != nil && inner.Code != "" {
+			b.WriteString("/" + string(inner.Code))
+		}
+		b.WriteString(": ")
+	}
+	b.WriteString(i.Message)
+}
+
+// innermostError returns the deepest inner error, or nil if there is none.
+func (i ErrorInformation) innermostError() *InnerError {
+	inner := i.InnerError
+	for inner != nil && inner.InnerError != nil {
+		inner = inner.InnerError
+	}
+	return inner
+}
+
+// IsRetryable reports whether a call that failed with err may succeed if sent again: on throttling, transient
+// service errors and transient transport errors. It is false for canceled calls, exceeded quotas and job
+// submissions in doubt, which may have created a job.
+//
+// The HTTP status of an error response decides, as it does for the retry policy; the error code is only consulted
+// when the status is unknown. Transport errors are retryable when they are timeouts, reset or refused connections
+// or connections closed by the service. An attempt that exceeded the timeout set with WithTimeout is retryable,
+// like the retry policy retries it, whereas the expiry or cancellation of the caller's context is not.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	var inDoubtErr *JobSubmissionInDoubtError
+	if errors.As(err, &inDoubtErr) {
+		return false
+	}
+	var timeoutErr *attemptTimeoutError
+	if errors.As(err, &timeoutErr) {
+		return true
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var taskErr *TaskError
+	if errors.As(err, &taskErr) {
+		if errors.Is(taskErr, ErrQuotaExceeded) {
+			return false
+		}
+		if taskErr.StatusCode >= http.StatusBadRequest {
+			return isRetryableStatus(taskErr.StatusCode) || taskErr.StatusCode == http.StatusGatewayTimeout
+		}
+		return errors.Is(taskErr, ErrTooManyRequests) || errors.Is(taskErr, ErrInternalServerError) ||
+			errors.Is(taskErr, ErrServiceUnavailable) || errors.Is(taskErr, ErrTimeout)
+	}
+	return isTransientTransportError(err)
+}
+
+// isTransientTransportError reports whether err is caused by a timeout, a reset or refused connection or a
+// connection closed by the service, whatever wraps it.
+func isTransientTransportError(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// attemptTimeoutError is the error of an attempt that exceeded the timeout set with WithTimeout.
+type attemptTimeoutError struct {
+	timeout time.Duration
+	err     error
+}
+
+func (e *attemptTimeoutError) Error() string {
+	return fmt.Sprintf("attempt timed out after %s: %v", e.timeout, e.err)
+}
+
+func (e *attemptTimeoutError) Unwrap() error {
+	return e.err
+}
+
+func (e *attemptTimeoutError) Timeout() bool {
+	return true
+}
+
+// DocumentErrors is returned together with the partial result by clients created with WithStrictDocumentErrors when
+// the service reported errors for some documents.
+type DocumentErrors struct {
+	// Errors Errors by document ID.
+	Errors []DocumentError
+}
+
+// IDs returns the IDs of the failed documents.
+func (e *DocumentErrors) IDs() []string {
+	ids := make([]string, len(e.Errors))
+	for i, docErr := range e.Errors {
+		ids[i] = docErr.ID
+	}
+	return ids
+}
+
+func (e *DocumentErrors) Error() string {
+	var b strings.Builder
+	if len(e.Errors) == 1 {
+		b.WriteString("1 document failed: ")
+	} else {
+		fmt.Fprintf(&b, "%d documents failed: ", len(e.Errors))
+	}
+	for i, docErr := range e.Errors {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(docErr.ID + " (")
+		docErr.Error.writeTo(&b)
+		b.WriteString(")")
+	}
+	return b.String()
 }
@@ -0,0 +1,216 @@
+package v20230401_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/kde713/azurelangai-go/textanalysis/v20230401"
+)
+
+func TestTaskError_ErrorTree(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("apim-request-id", "req-1")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":{"code":"InvalidRequest","message":"Invalid Request.",` +
+			`"details":[{"code":"InvalidArgument","message":"Invalid document.","target":"documents"}],` +
+			`"innererror":{"code":"InvalidDocumentBatch","message":"Batch request contains too many records.",` +
+			`"details":{"maxDocuments":"5"},"innererror":{"code":"InvalidDocument","message":"Document is empty."}}}}`))
+	}))
+	defer srv.Close()
+
+	client := v20230401.NewClient(srv.URL, "key")
+	_, err := client.GetTextAnalyticsJobResult(context.Background(), "job-1")
+	var taskErr *v20230401.TaskError
+	if !errors.As(err, &taskErr) {
+		t.Fatalf("Expected TaskError, got %v", err)
+	}
+	info := taskErr.Information
+	if info.Code != v20230401.ErrorCodeInvalidRequest {
+		t.Errorf("Expected code InvalidRequest, got %s", info.Code)
+	}
+	if len(info.Details) != 1 || info.Details[0].Code != v20230401.ErrorCodeInvalidArgument || info.Details[0].Target != "documents" {
+		t.Errorf("Expected one InvalidArgument detail, got %+v", info.Details)
+	}
+	if info.InnerError == nil || info.InnerError.Code != v20230401.InnerErrorCodeInvalidDocumentBatch {
+		t.Fatalf("Expected inner error InvalidDocumentBatch, got %+v", info.InnerError)
+	}
+	if info.InnerError.Details["maxDocuments"] != "5" {
+		t.Errorf("Expected inner error detail maxDocuments 5, got %v", info.InnerError.Details)
+	}
+	if info.InnerError.InnerError == nil || info.InnerError.InnerError.Code != v20230401.InnerErrorCodeInvalidDocument {
+		t.Errorf("Expected nested inner error InvalidDocument, got %+v", info.InnerError.InnerError)
+	}
+	expected := "task failed: InvalidRequest/InvalidDocument: Invalid Request. (status 400, apim-request-id req-1)"
+	if err.Error() != expected {
+		t.Errorf("Expected %q, got %q", expected, err.Error())
+	}
+	if !errors.Is(err, v20230401.ErrInvalidRequest) {
+		t.Errorf("Expected error to match ErrInvalidRequest")
+	}
+}
+
+func TestTaskError_Is(t *testing.T) {
+	tests := []struct {
+		err      *v20230401.TaskError
+		target   error
+		expected bool
+	}{
+		{&v20230401.TaskError{Information: v20230401.ErrorInformation{Code: v20230401.ErrorCodeProjectNotFound}, StatusCode: 404}, v20230401.ErrProjectNotFound, true},
+		{&v20230401.TaskError{Information: v20230401.ErrorInformation{Code: v20230401.ErrorCodeProjectNotFound}, StatusCode: 404}, v20230401.ErrNotFound, true},
+		{&v20230401.TaskError{Information: v20230401.ErrorInformation{Code: v20230401.ErrorCodeNotFound}, StatusCode: 404}, v20230401.ErrProjectNotFound, false},
+		{&v20230401.TaskError{Information: v20230401.ErrorInformation{Code: v20230401.ErrorCodeQuotaExceeded}, StatusCode: 403}, v20230401.ErrQuotaExceeded, true},
+		{&v20230401.TaskError{Information: v20230401.ErrorInformation{Code: v20230401.ErrorCodeQuotaExceeded}, StatusCode: 403}, v20230401.ErrForbidden, false},
+		{&v20230401.TaskError{StatusCode: 401}, v20230401.ErrUnauthorized, true},
+		{&v20230401.TaskError{StatusCode: 429}, v20230401.ErrTooManyRequests, true},
+		{&v20230401.TaskError{StatusCode: 500}, v20230401.ErrNotFound, false},
+		{&v20230401.TaskError{Information: v20230401.ErrorInformation{Code: "401"}, StatusCode: 401}, v20230401.ErrUnauthorized, true},
+		{&v20230401.TaskError{Information: v20230401.ErrorInformation{Code: "429"}, StatusCode: 429}, v20230401.ErrTooManyRequests, true},
+		{&v20230401.TaskError{Information: v20230401.ErrorInformation{Code: "429"}, StatusCode: 429}, v20230401.ErrQuotaExceeded, false},
+	}
+	for _, test := range tests {
+		wrapped := fmt.Errorf("wrapped: %w", test.err)
+		if actual := errors.Is(wrapped, test.target); actual != test.expected {
+			t.Errorf("Expected errors.Is(%v, %v) to be %v, got %v", test.err, test.target, test.expected, actual)
+		}
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"nil", nil, false},
+		{"throttled", &v20230401.TaskError{Information: v20230401.ErrorInformation{Code: v20230401.ErrorCodeTooManyRequests}, StatusCode: 429}, true},
+		{"search throttled", &v20230401.TaskError{Information: v20230401.ErrorInformation{Code: v20230401.ErrorCodeAzureCognitiveSearchThrottling}, StatusCode: 400}, false},
+		{"search throttled without status", &v20230401.TaskError{Information: v20230401.ErrorInformation{Code: v20230401.ErrorCodeAzureCognitiveSearchThrottling}}, true},
+		{"gateway code", &v20230401.TaskError{Information: v20230401.ErrorInformation{Code: "503"}, StatusCode: 503}, true},
+		{"internal error code with client status", &v20230401.TaskError{Information: v20230401.ErrorInformation{Code: v20230401.ErrorCodeInternalServerError}, StatusCode: 400}, false},
+		{"quota exceeded", &v20230401.TaskError{Information: v20230401.ErrorInformation{Code: v20230401.ErrorCodeQuotaExceeded}, StatusCode: 429}, false},
+		{"gateway timeout", &v20230401.TaskError{StatusCode: 504}, true},
+		{"bad gateway", &v20230401.TaskError{StatusCode: 502}, true},
+		{"invalid request", &v20230401.TaskError{Information: v20230401.ErrorInformation{Code: v20230401.ErrorCodeInvalidRequest}, StatusCode: 400}, false},
+		{"canceled", context.Canceled, false},
+		{"caller deadline", &url.Error{Op: "Get", URL: "http://example.com", Err: context.DeadlineExceeded}, false},
+		{"connection reset", &url.Error{Op: "Get", URL: "http://example.com", Err: &net.OpError{Op: "read", Err: os.NewSyscallError("read", syscall.ECONNRESET)}}, true},
+		{"connection closed", &url.Error{Op: "Get", URL: "http://example.com", Err: io.EOF}, true},
+		{"network timeout", &url.Error{Op: "Get", URL: "http://example.com", Err: &net.DNSError{Err: "timeout", IsTimeout: true}}, true},
+		{"invalid URL", &url.Error{Op: "parse", URL: "::", Err: errors.New("missing protocol scheme")}, false},
+		{"in doubt", &v20230401.JobSubmissionInDoubtError{Err: errors.New("connection reset")}, false},
+		{"other", errors.New("other"), false},
+	}
+	for _, test := range tests {
+		if actual := v20230401.IsRetryable(test.err); actual != test.expected {
+			t.Errorf("%s: Expected %v, got %v", test.name, test.expected, actual)
+		}
+	}
+
+	client := v20230401.NewClient("http://127.0.0.1:1", "key")
+	_, err := client.GetTextAnalyticsJobResult(context.Background(), "job-1")
+	if !v20230401.IsRetryable(err) {
+		t.Errorf("Expected transport error %v to be retryable", err)
+	}
+}
+
+func TestIsRetryable_Timeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	client := v20230401.NewClient(srv.URL, "key", v20230401.WithTimeout(10*time.Millisecond))
+	_, err := client.GetTextAnalyticsJobResult(context.Background(), "job-1")
+	if !errors.Is(err, context.DeadlineExceeded) || !v20230401.IsRetryable(err) {
+		t.Errorf("Expected the per-attempt timeout %v to be retryable", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = v20230401.NewClient(srv.URL, "key").GetTextAnalyticsJobResult(ctx, "job-1")
+	if !errors.Is(err, context.DeadlineExceeded) || v20230401.IsRetryable(err) {
+		t.Errorf("Expected the expiry of the caller's deadline %v not to be retryable", err)
+	}
+}
+
+const partialKeyPhraseResults = `{"kind":"KeyPhraseExtractionResults","results":{"modelVersion":"2022-10-01",` +
+	`"documents":[{"id":"1","keyPhrases":["word"],"warnings":[{"code":"LongWordsInDocument","message":"Long words.","targetRef":"#/documents/0"}]}],` +
+	`"errors":[{"id":"2","error":{"code":"InvalidArgument","message":"Invalid document in request.",` +
+	`"innererror":{"code":"InvalidDocument","message":"Document text is empty."}}}]}}`
+
+func TestWithStrictDocumentErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(partialKeyPhraseResults))
+	}))
+	defer srv.Close()
+	input := v20230401.MultiLanguageAnalysisInput{Documents: []v20230401.MultiLanguageInput{{ID: "1", Text: "word"}, {ID: "2", Text: ""}}}
+
+	result, err := v20230401.NewClient(srv.URL, "key").AnalyzeTextKeyPhraseExtraction(context.Background(), input, v20230401.KeyPhraseTaskParameters{})
+	if err != nil {
+		t.Fatalf("Expected no error without strict mode, got %v", err)
+	}
+	if len(result.Errors) != 1 {
+		t.Errorf("Expected 1 document error, got %d", len(result.Errors))
+	}
+
+	client := v20230401.NewClient(srv.URL, "key", v20230401.WithStrictDocumentErrors())
+	result, err = client.AnalyzeTextKeyPhraseExtraction(context.Background(), input, v20230401.KeyPhraseTaskParameters{})
+	var docErrs *v20230401.DocumentErrors
+	if !errors.As(err, &docErrs) {
+		t.Fatalf("Expected DocumentErrors, got %v", err)
+	}
+	if ids := docErrs.IDs(); len(ids) != 1 || ids[0] != "2" {
+		t.Errorf("Expected failed IDs [2], got %v", ids)
+	}
+	expected := "1 document failed: 2 (InvalidArgument/InvalidDocument: Invalid document in request.)"
+	if err.Error() != expected {
+		t.Errorf("Expected %q, got %q", expected, err.Error())
+	}
+	if result == nil || len(result.Documents) != 1 {
+		t.Fatalf("Expected partial result with 1 document, got %+v", result)
+	}
+	if warnings := result.Documents[0].Warnings; len(warnings) != 1 || warnings[0].Code != v20230401.WarningCodeLongWordsInDocument {
+		t.Errorf("Expected LongWordsInDocument warning, got %+v", warnings)
+	}
+}
+
+func TestWithStrictDocumentErrors_Job(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := "running"
+		if atomic.AddInt32(&calls, 1) > 1 {
+			status = "succeeded"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"jobId":"job-1","status":%q,"tasks":{"completed":1,"failed":0,"inProgress":0,"total":1,"items":[`+
+			`{"kind":"KeyPhraseExtractionLROResults","taskName":"task","status":"succeeded","results":%s}]}}`,
+			status, `{"modelVersion":"2022-10-01","documents":[],"errors":[{"id":"2","error":{"code":"InvalidArgument","message":"Invalid document in request."}}]}`)
+	}))
+	defer srv.Close()
+	client := v20230401.NewClient(srv.URL, "key", v20230401.WithStrictDocumentErrors())
+
+	if _, err := client.GetTextAnalyticsJobResult(context.Background(), "job-1"); err != nil {
+		t.Fatalf("Expected no error while the job is running, got %v", err)
+	}
+	jobResp, err := client.GetTextAnalyticsJobResult(context.Background(), "job-1")
+	var docErrs *v20230401.DocumentErrors
+	if !errors.As(err, &docErrs) || len(docErrs.Errors) != 1 {
+		t.Fatalf("Expected DocumentErrors with 1 error, got %v", err)
+	}
+	if jobResp == nil || jobResp.Status != v20230401.StatusSucceeded {
+		t.Errorf("Expected succeeded job response, got %+v", jobResp)
+	}
+}
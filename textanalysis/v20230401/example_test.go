@@ -24,6 +24,16 @@ func ExampleNewClient() {
 	)
 }
 
+func ExampleNewClientWithTokenCredential() {
+	// Use the custom subdomain endpoint of your resource.
+	endpoint := "https://<this-is-example>.cognitiveservices.azure.com/"
+
+	// Authenticate with the managed identity of the host instead of a subscription key.
+	credential := azuretextanalysis.NewManagedIdentityCredential("")
+
+	azureTextAnalysisClient = azuretextanalysis.NewClientWithTokenCredential(endpoint, credential)
+}
+
 func ExampleClient_AnalyzeTextLanguageDetection() {
 	// Create an input.
 	input := azuretextanalysis.LanguageDetectionAnalysisInput{
@@ -106,3 +116,23 @@ func ExampleClient_SubmitTextAnalyticsJob() {
 		}
 	}
 }
+
+func ExampleJobPoller_Wait() {
+	jobID := "<job-id-from-SubmitTextAnalyticsJob>"
+
+	// Poll every second, backing off up to 10 seconds, and give up after 5 minutes.
+	poller := azuretextanalysis.NewJobPoller(azureTextAnalysisClient, azuretextanalysis.PollingPolicy{
+		Interval:    time.Second,
+		Multiplier:  1.5,
+		MaxInterval: 10 * time.Second,
+		MaxWait:     5 * time.Minute,
+	}).OnProgress(func(p azuretextanalysis.JobProgress) {
+		fmt.Printf("Job %s: %d/%d tasks completed\n", p.JobID, p.Completed, p.Total)
+	})
+
+	jobResult, err := poller.Wait(context.TODO(), jobID)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Printf("Job finished with status %s\n", jobResult.Status)
+}
@@ -0,0 +1,164 @@
+package v20230401
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// ErrJobSubmissionPending is wrapped in the *JobSubmissionInDoubtError returned by SubmitTextAnalyticsJob with
+// WithJobDedup when an earlier submission of the same request is still running elsewhere or ended in doubt without
+// a job ID. Delete the record of the request from the JobDedupStore to submit it again.
+var ErrJobSubmissionPending = errors.New("an earlier submission of the request is pending or in doubt")
+
+// JobSubmissionInDoubtError is returned by SubmitTextAnalyticsJob when the submission failed in a way that leaves
+// it unknown whether the service accepted the job: the connection failed after the request was sent, or the
+// service answered with a 5xx status. The request is not retried automatically because that could create a
+// duplicate job. Check for an existing job before resubmitting.
+type JobSubmissionInDoubtError struct {
+	// Key Dedup key of the submitted request, see JobRequestKey.
+	Key string
+	// JobID (Optional) ID of the job from the Operation-Location header of the failed response.
+	JobID string
+	// Err Underlying error.
+	Err error
+}
+
+func (e *JobSubmissionInDoubtError) Error() string {
+	return fmt.Sprintf("job submission in doubt: %v", e.Err)
+}
+
+func (e *JobSubmissionInDoubtError) Unwrap() error {
+	return e.Err
+}
+
+// JobDedupStore records the jobs submitted per request, so that submitting the same request again returns the
+// existing job instead of creating a duplicate. A record with an empty job ID marks a submission that is pending or
+// ended in doubt. Implementations must be safe for concurrent use.
+type JobDedupStore interface {
+	// Load returns the job ID recorded for key.
+	Load(ctx context.Context, key string) (jobID string, ok bool, err error)
+	// Store records jobID for key.
+	Store(ctx context.Context, key string, jobID string) error
+	// Delete removes the record of key.
+	Delete(ctx context.Context, key string) error
+}
+
+// JobRequestKey returns the dedup key of input: the hex-encoded SHA-256 of its JSON encoding.
+func JobRequestKey(input SubmitJobRequestBody) (string, error) {
+	body, err := json.Marshal(input)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode job request: %w", err)
+	}
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+type memoryJobDedupEntry struct {
+	jobID    string
+	storedAt time.Time
+}
+
+// MemoryJobDedupStore is an in-process JobDedupStore whose records expire after a TTL.
+type MemoryJobDedupStore struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]memoryJobDedupEntry
+}
+
+var _ JobDedupStore = (*MemoryJobDedupStore)(nil)
+
+// NewMemoryJobDedupStore creates a MemoryJobDedupStore. A zero ttl defaults to 24 hours, which is how long the
+// service keeps job results.
+func NewMemoryJobDedupStore(ttl time.Duration) *MemoryJobDedupStore {
+	if ttl <= 0 {
+		ttl = 24 * time.Hour
+	}
+	return &MemoryJobDedupStore{ttl: ttl, entries: make(map[string]memoryJobDedupEntry)}
+}
+
+func (s *MemoryJobDedupStore) Load(_ context.Context, key string) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.entries[key]
+	if !ok {
+		return "", false, nil
+	}
+	if time.Since(entry.storedAt) > s.ttl {
+		delete(s.entries, key)
+		return "", false, nil
+	}
+	return entry.jobID, true, nil
+}
+
+func (s *MemoryJobDedupStore) Store(_ context.Context, key string, jobID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	for k, entry := range s.entries {
+		if now.Sub(entry.storedAt) > s.ttl {
+			delete(s.entries, k)
+		}
+	}
+	s.entries[key] = memoryJobDedupEntry{jobID: jobID, storedAt: now}
+	return nil
+}
+
+func (s *MemoryJobDedupStore) Delete(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, key)
+	return nil
+}
+
+// isDialError reports whether err happened while connecting, before the request was sent.
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// jobSubmissionCall is a job submission shared by concurrent callers with the same request.
+type jobSubmissionCall struct {
+	done  chan struct{}
+	jobID string
+	err   error
+}
+
+type inflightJobSubmissions struct {
+	mu    sync.Mutex
+	calls map[string]*jobSubmissionCall
+}
+
+// do runs fn once for concurrent callers with the same key and shares its result.
+func (s *inflightJobSubmissions) do(ctx context.Context, key string, fn func() (string, error)) (string, error) {
+	s.mu.Lock()
+	if call, ok := s.calls[key]; ok {
+		s.mu.Unlock()
+		select {
+		case <-call.done:
+			return call.jobID, call.err
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+	call := &jobSubmissionCall{done: make(chan struct{})}
+	if s.calls == nil {
+		s.calls = make(map[string]*jobSubmissionCall)
+	}
+	s.calls[key] = call
+	s.mu.Unlock()
+
+	call.jobID, call.err = fn()
+	s.mu.Lock()
+	delete(s.calls, key)
+	s.mu.Unlock()
+	close(call.done)
+	return call.jobID, call.err
+}
@@ -0,0 +1,219 @@
+package v20230401_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kde713/azurelangai-go/textanalysis/v20230401"
+)
+
+// newSubmitServer answers job submissions with statuses in order, then accepts every further submission as a
+// new job. Like a gateway failing after the job was created, error responses carry the Operation-Location of a
+// new job.
+func newSubmitServer(t *testing.T, delay time.Duration, statuses ...int) (*httptest.Server, *int32) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&requests, 1)
+		time.Sleep(delay)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Operation-Location", fmt.Sprintf("http://%s/language/analyze-text/jobs/job-%d?api-version=%s", r.Host, n, v20230401.APIVersion))
+		if int(n) <= len(statuses) && statuses[n-1] != http.StatusAccepted {
+			w.WriteHeader(statuses[n-1])
+			_, _ = w.Write([]byte(`{"error":{"code":"InternalServerError","message":"Internal server error."}}`))
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+func newTestJobRequest(t *testing.T, text string) v20230401.SubmitJobRequestBody {
+	builder := v20230401.NewJobBuilder("test", v20230401.MultiLanguageAnalysisInput{
+		Documents: []v20230401.MultiLanguageInput{{ID: "1", Text: text, Language: "en"}},
+	})
+	builder.AddKeyPhraseExtractionTask("keyPhrases", v20230401.KeyPhraseTaskParameters{})
+	body, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return *body
+}
+
+func TestSubmitTextAnalyticsJob_NoRetryOnServerError(t *testing.T) {
+	srv, requests := newSubmitServer(t, 0, http.StatusInternalServerError)
+	client := v20230401.NewClient(srv.URL, "key", v20230401.WithRetryCount(3, time.Millisecond, time.Millisecond))
+
+	_, err := client.SubmitTextAnalyticsJob(context.TODO(), newTestJobRequest(t, "hello"))
+	var inDoubtErr *v20230401.JobSubmissionInDoubtError
+	if !errors.As(err, &inDoubtErr) {
+		t.Fatalf("Expected JobSubmissionInDoubtError, got %v", err)
+	}
+	var taskErr *v20230401.TaskError
+	if !errors.As(err, &taskErr) {
+		t.Errorf("Expected wrapped TaskError, got %v", inDoubtErr.Err)
+	}
+	if inDoubtErr.JobID != "job-1" {
+		t.Errorf("Expected job-1 from Operation-Location, got %q", inDoubtErr.JobID)
+	}
+	if got := atomic.LoadInt32(requests); got != 1 {
+		t.Errorf("Expected 1 request, got %d", got)
+	}
+}
+
+func TestSubmitTextAnalyticsJob_RetryOnThrottling(t *testing.T) {
+	srv, requests := newSubmitServer(t, 0, http.StatusTooManyRequests)
+	client := v20230401.NewClient(srv.URL, "key", v20230401.WithRetryCount(3, time.Millisecond, time.Millisecond))
+
+	jobID, err := client.SubmitTextAnalyticsJob(context.TODO(), newTestJobRequest(t, "hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if jobID != "job-2" {
+		t.Errorf("Expected job-2, got %s", jobID)
+	}
+	if got := atomic.LoadInt32(requests); got != 2 {
+		t.Errorf("Expected 2 requests, got %d", got)
+	}
+}
+
+func TestSubmitTextAnalyticsJob_Dedup(t *testing.T) {
+	srv, requests := newSubmitServer(t, 0, http.StatusInternalServerError)
+	client := v20230401.NewClient(srv.URL, "key", v20230401.WithJobDedup(v20230401.NewMemoryJobDedupStore(0)))
+
+	_, err := client.SubmitTextAnalyticsJob(context.TODO(), newTestJobRequest(t, "hello"))
+	var inDoubtErr *v20230401.JobSubmissionInDoubtError
+	if !errors.As(err, &inDoubtErr) {
+		t.Fatalf("Expected JobSubmissionInDoubtError, got %v", err)
+	}
+	first, err := client.SubmitTextAnalyticsJob(context.TODO(), newTestJobRequest(t, "hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := client.SubmitTextAnalyticsJob(context.TODO(), newTestJobRequest(t, "hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != "job-1" || second != first {
+		t.Errorf("Expected job-1 twice, got %s and %s", first, second)
+	}
+	other, err := client.SubmitTextAnalyticsJob(context.TODO(), newTestJobRequest(t, "world"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other != "job-2" {
+		t.Errorf("Expected job-2, got %s", other)
+	}
+	if got := atomic.LoadInt32(requests); got != 2 {
+		t.Errorf("Expected 2 requests, got %d", got)
+	}
+}
+
+func TestSubmitTextAnalyticsJob_DedupPending(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			// Drop the connection after the request was received.
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			_ = conn.Close()
+			return
+		}
+		w.Header().Set("Operation-Location", fmt.Sprintf("http://%s/language/analyze-text/jobs/job-2?api-version=%s", r.Host, v20230401.APIVersion))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+	store := v20230401.NewMemoryJobDedupStore(0)
+	client := v20230401.NewClient(srv.URL, "key", v20230401.WithJobDedup(store))
+	input := newTestJobRequest(t, "hello")
+
+	_, err := client.SubmitTextAnalyticsJob(context.TODO(), input)
+	var inDoubtErr *v20230401.JobSubmissionInDoubtError
+	if !errors.As(err, &inDoubtErr) {
+		t.Fatalf("Expected JobSubmissionInDoubtError, got %v", err)
+	}
+	_, err = client.SubmitTextAnalyticsJob(context.TODO(), input)
+	if !errors.Is(err, v20230401.ErrJobSubmissionPending) {
+		t.Fatalf("Expected ErrJobSubmissionPending, got %v", err)
+	}
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Errorf("Expected 1 request while the submission is in doubt, got %d", got)
+	}
+
+	if err := store.Delete(context.TODO(), inDoubtErr.Key); err != nil {
+		t.Fatal(err)
+	}
+	jobID, err := client.SubmitTextAnalyticsJob(context.TODO(), input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if jobID != "job-2" {
+		t.Errorf("Expected job-2, got %s", jobID)
+	}
+}
+
+func TestSubmitTextAnalyticsJob_ConnectionRefused(t *testing.T) {
+	client := v20230401.NewClient("http://127.0.0.1:1", "key", v20230401.WithJobDedup(v20230401.NewMemoryJobDedupStore(0)))
+	input := newTestJobRequest(t, "hello")
+
+	for i := 0; i < 2; i++ {
+		_, err := client.SubmitTextAnalyticsJob(context.TODO(), input)
+		if err == nil {
+			t.Fatal("Expected error")
+		}
+		var inDoubtErr *v20230401.JobSubmissionInDoubtError
+		if errors.As(err, &inDoubtErr) {
+			t.Errorf("Expected a connection refused submission not to be in doubt, got %v", err)
+		}
+	}
+}
+
+func TestSubmitTextAnalyticsJob_DedupConcurrent(t *testing.T) {
+	srv, requests := newSubmitServer(t, 50*time.Millisecond)
+	client := v20230401.NewClient(srv.URL, "key", v20230401.WithJobDedup(v20230401.NewMemoryJobDedupStore(time.Hour)))
+
+	input := newTestJobRequest(t, "hello")
+	var wg sync.WaitGroup
+	jobIDs := make([]string, 5)
+	errs := make([]error, 5)
+	for i := range jobIDs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			jobIDs[i], errs[i] = client.SubmitTextAnalyticsJob(context.TODO(), input)
+		}(i)
+	}
+	wg.Wait()
+	for i := range jobIDs {
+		if errs[i] != nil {
+			t.Fatal(errs[i])
+		}
+		if jobIDs[i] != "job-1" {
+			t.Errorf("Expected job-1, got %s", jobIDs[i])
+		}
+	}
+	if got := atomic.LoadInt32(requests); got != 1 {
+		t.Errorf("Expected 1 request, got %d", got)
+	}
+}
+
+func TestMemoryJobDedupStore_Expiry(t *testing.T) {
+	store := v20230401.NewMemoryJobDedupStore(time.Millisecond)
+	if err := store.Store(context.TODO(), "key", "job-1"); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if _, ok, _ := store.Load(context.TODO(), "key"); ok {
+		t.Error("Expected record to expire")
+	}
+}
@@ -0,0 +1,167 @@
+package v20230401
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// KeyProvider supplies the subscription key of every request. Implementations must be safe for concurrent use.
+type KeyProvider interface {
+	// Key returns the subscription key to use for the next request.
+	Key(ctx context.Context) (string, error)
+	// Rejected is called when the service rejected key with 401 Unauthorized. It reports whether another key is
+	// now available from Key, in which case the request is retried once.
+	Rejected(ctx context.Context, key string) bool
+}
+
+type staticKey string
+
+// StaticKey returns a KeyProvider that always returns key.
+func StaticKey(key string) KeyProvider {
+	return staticKey(key)
+}
+
+func (k staticKey) Key(context.Context) (string, error) {
+	return string(k), nil
+}
+
+func (k staticKey) Rejected(context.Context, string) bool {
+	return false
+}
+
+// KeyPair holds the primary and secondary keys of a resource and switches to the other key when the active one
+// is rejected, which is what happens while one of them is being regenerated.
+type KeyPair struct {
+	mu              sync.RWMutex
+	primary         string
+	secondary       string
+	secondaryActive bool
+}
+
+var _ KeyProvider = (*KeyPair)(nil)
+
+// NewKeyPair creates a KeyPair that starts with the primary key.
+func NewKeyPair(primary string, secondary string) *KeyPair {
+	return &KeyPair{primary: primary, secondary: secondary}
+}
+
+func (p *KeyPair) Key(context.Context) (string, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.secondaryActive {
+		return p.secondary, nil
+	}
+	return p.primary, nil
+}
+
+func (p *KeyPair) Rejected(_ context.Context, key string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	active := p.primary
+	if p.secondaryActive {
+		active = p.secondary
+	}
+	if key != active {
+		// Another request already switched keys.
+		return key != "" && active != ""
+	}
+	if p.primary == p.secondary {
+		return false
+	}
+	p.secondaryActive = !p.secondaryActive
+	return true
+}
+
+// SetKeys replaces both keys, e.g. after one of them has been regenerated. The active slot is kept.
+func (p *KeyPair) SetKeys(primary string, secondary string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.primary = primary
+	p.secondary = secondary
+}
+
+// UsePrimary makes the primary key active.
+func (p *KeyPair) UsePrimary() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.secondaryActive = false
+}
+
+// UseSecondary makes the secondary key active.
+func (p *KeyPair) UseSecondary() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.secondaryActive = true
+}
+
+// FileKeyProvider reads the subscription key from a file, such as a mounted Kubernetes secret, and reloads it
+// when the file changes.
+type FileKeyProvider struct {
+	path          string
+	checkInterval time.Duration
+
+	mu        sync.Mutex
+	key       string
+	modTime   time.Time
+	checkedAt time.Time
+}
+
+var _ KeyProvider = (*FileKeyProvider)(nil)
+
+// NewFileKeyProvider creates a FileKeyProvider. The file modification time is checked at most once per
+// checkInterval; a zero interval checks it before every request. Surrounding whitespace in the file is ignored.
+func NewFileKeyProvider(path string, checkInterval time.Duration) (*FileKeyProvider, error) {
+	p := &FileKeyProvider{path: path, checkInterval: checkInterval}
+	if err := p.reload(true); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *FileKeyProvider) Key(context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if time.Since(p.checkedAt) >= p.checkInterval {
+		if err := p.reload(false); err != nil && p.key == "" {
+			return "", err
+		}
+	}
+	return p.key, nil
+}
+
+// Rejected reloads the file and reports whether it now holds a different key.
+func (p *FileKeyProvider) Rejected(_ context.Context, key string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.reload(true); err != nil {
+		return false
+	}
+	return p.key != key
+}
+
+func (p *FileKeyProvider) reload(force bool) error {
+	p.checkedAt = time.Now()
+	info, err := os.Stat(p.path)
+	if err != nil {
+		return fmt.Errorf("failed to stat key file: %w", err)
+	}
+	if !force && info.ModTime().Equal(p.modTime) {
+		return nil
+	}
+	content, err := os.ReadFile(p.path)
+	if err != nil {
+		return fmt.Errorf("failed to read key file: %w", err)
+	}
+	key := strings.TrimSpace(string(content))
+	if key == "" {
+		return errors.New("key file is empty")
+	}
+	p.key = key
+	p.modTime = info.ModTime()
+	return nil
+}
@@ -0,0 +1,111 @@
+package v20230401_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kde713/azurelangai-go/textanalysis/v20230401"
+)
+
+// newKeyCheckingServer accepts job status requests only with the given subscription key.
+func newKeyCheckingServer(t *testing.T, validKey *atomic.Value) (*httptest.Server, *int32) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("Content-Type", "application/json")
+		if r.Header.Get("Ocp-Apim-Subscription-Key") != validKey.Load().(string) {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":{"code":"401","message":"Access denied due to invalid subscription key."}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"jobId":"job-1","status":"running","tasks":{"items":[]}}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+func TestNewClient_Unauthorized(t *testing.T) {
+	var validKey atomic.Value
+	validKey.Store("valid")
+	srv, requests := newKeyCheckingServer(t, &validKey)
+	client := v20230401.NewClient(srv.URL, "invalid")
+
+	_, err := client.GetTextAnalyticsJobResult(context.TODO(), "job-1")
+	var taskErr *v20230401.TaskError
+	if !errors.As(err, &taskErr) {
+		t.Fatalf("Expected TaskError, got %v", err)
+	}
+	if got := atomic.LoadInt32(requests); got != 1 {
+		t.Errorf("Expected 1 request, got %d", got)
+	}
+}
+
+func TestKeyPair_SwitchOnUnauthorized(t *testing.T) {
+	var validKey atomic.Value
+	validKey.Store("secondary")
+	srv, requests := newKeyCheckingServer(t, &validKey)
+	keys := v20230401.NewKeyPair("primary", "secondary")
+	client := v20230401.NewClientWithKeyProvider(srv.URL, keys)
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetTextAnalyticsJobResult(context.TODO(), "job-1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := atomic.LoadInt32(requests); got != 3 {
+		t.Errorf("Expected 3 requests (rejected, retried, direct), got %d", got)
+	}
+	if key, _ := keys.Key(context.TODO()); key != "secondary" {
+		t.Errorf("Expected secondary key to be active, got %s", key)
+	}
+
+	// Both keys rejected: the request is retried only once.
+	validKey.Store("regenerated")
+	if _, err := client.GetTextAnalyticsJobResult(context.TODO(), "job-1"); err == nil {
+		t.Fatal("Expected error")
+	}
+	if got := atomic.LoadInt32(requests); got != 5 {
+		t.Errorf("Expected 5 requests, got %d", got)
+	}
+}
+
+func TestFileKeyProvider(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "key")
+	if err := os.WriteFile(keyFile, []byte("old-key\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	keys, err := v20230401.NewFileKeyProvider(keyFile, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var validKey atomic.Value
+	validKey.Store("old-key")
+	srv, requests := newKeyCheckingServer(t, &validKey)
+	client := v20230401.NewClientWithKeyProvider(srv.URL, keys)
+
+	if _, err := client.GetTextAnalyticsJobResult(context.TODO(), "job-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Rotate the key: the cached key is rejected and the file is reloaded.
+	validKey.Store("new-key")
+	if err := os.WriteFile(keyFile, []byte("new-key\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetTextAnalyticsJobResult(context.TODO(), "job-1"); err != nil {
+		t.Fatal(err)
+	}
+	if got := atomic.LoadInt32(requests); got != 3 {
+		t.Errorf("Expected 3 requests, got %d", got)
+	}
+	if key, _ := keys.Key(context.TODO()); key != "new-key" {
+		t.Errorf("Expected new-key, got %s", key)
+	}
+}
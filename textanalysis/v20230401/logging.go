@@ -0,0 +1,225 @@
+package v20230401
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// Logger receives structured log events as alternating key-value pairs. *slog.Logger implements it.
+type Logger interface {
+	DebugContext(ctx context.Context, msg string, args ...interface{})
+	WarnContext(ctx context.Context, msg string, args ...interface{})
+	ErrorContext(ctx context.Context, msg string, args ...interface{})
+}
+
+type logOptions struct {
+	bodies      bool
+	maxBodySize int
+	unredacted  bool
+}
+
+type LogOption func(*logOptions)
+
+// WithLogBodies adds the request and response bodies and headers to log events, truncated to maxSize bytes.
+// A zero maxSize defaults to 4096 bytes.
+func WithLogBodies(maxSize int) LogOption {
+	return func(o *logOptions) {
+		o.bodies = true
+		o.maxBodySize = maxSize
+	}
+}
+
+// WithLogUnredactedText keeps document texts in captured bodies. Credentials are redacted regardless.
+func WithLogUnredactedText() LogOption {
+	return func(o *logOptions) {
+		o.unredacted = true
+	}
+}
+
+const redacted = "REDACTED"
+
+// redactedHeaders are replaced in captured headers.
+var redactedHeaders = []string{subscriptionKeyHeader, "Authorization"}
+
+// redactedBodyKeys are the JSON keys whose values are document text or taken from it.
+var redactedBodyKeys = map[string]bool{
+	"text":         true,
+	"redactedText": true,
+	"keyPhrases":   true,
+}
+
+// documentMetadataKeys are the JSON keys kept within documents, besides the document ID. Their other string values
+// are all redacted since they may be taken from the document text, e.g. linked entity names or summary sentences.
+var documentMetadataKeys = map[string]bool{
+	"kind":        true,
+	"language":    true,
+	"category":    true,
+	"subcategory": true,
+	"sentiment":   true,
+	"code":        true,
+	"targetRef":   true,
+}
+
+type requestLogger struct {
+	logger Logger
+	logOptions
+}
+
+func newRequestLogger(logger Logger, optAppliers []LogOption) *requestLogger {
+	o := logOptions{}
+	for _, applier := range optAppliers {
+		applier(&o)
+	}
+	if o.maxBodySize <= 0 {
+		o.maxBodySize = 4096
+	}
+	return &requestLogger{logger: logger, logOptions: o}
+}
+
+// logAttempt emits an event for an attempt of a request: at debug level if it succeeded, at warn level if the
+// service returned an error status and at error level if no response was received.
+func (l *requestLogger) logAttempt(req *resty.Request, method string, url string, resp *resty.Response, err error, attempt int, duration time.Duration) {
+	if l == nil {
+		return
+	}
+	ctx := req.Context()
+	path := url
+	if req.RawRequest != nil {
+		path = req.RawRequest.URL.Path
+	}
+	args := []interface{}{
+		"method", method,
+		"path", path,
+		"api_version", APIVersion,
+		"attempt", attempt,
+		"duration", duration,
+	}
+	if resp != nil && resp.RawResponse != nil {
+		args = append(args, "status", resp.StatusCode())
+		if requestID := resp.Header().Get("apim-request-id"); requestID != "" {
+			args = append(args, "request_id", requestID)
+		}
+	}
+	if l.bodies {
+		header := req.Header
+		if req.RawRequest != nil {
+			header = req.RawRequest.Header
+		}
+		args = append(args, "request_headers", l.headers(header), "request_body", l.requestBody(req.Body))
+		if resp != nil && resp.RawResponse != nil {
+			args = append(args, "response_body", l.body(resp.Body()))
+		}
+	}
+	switch {
+	case err != nil:
+		l.logger.ErrorContext(ctx, "language request failed", append(args, "error", err)...)
+	case resp.IsError():
+		l.logger.WarnContext(ctx, "language request returned error status", args...)
+	default:
+		l.logger.DebugContext(ctx, "language request", args...)
+	}
+}
+
+func (l *requestLogger) headers(header http.Header) map[string]string {
+	headers := make(map[string]string, len(header))
+	for name := range header {
+		headers[name] = header.Get(name)
+	}
+	for _, name := range redactedHeaders {
+		if header.Get(name) != "" {
+			headers[http.CanonicalHeaderKey(name)] = redacted
+		}
+	}
+	return headers
+}
+
+func (l *requestLogger) requestBody(body interface{}) string {
+	if body == nil {
+		return ""
+	}
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Sprintf("<unencodable body: %v>", err)
+	}
+	return l.body(encoded)
+}
+
+// body redacts document text from a JSON body and truncates it.
+func (l *requestLogger) body(body []byte) string {
+	if !l.unredacted && len(body) > 0 {
+		var decoded interface{}
+		if err := json.Unmarshal(body, &decoded); err != nil {
+			return "<non-JSON body redacted>"
+		}
+		redactJSON(decoded)
+		if encoded, err := json.Marshal(decoded); err == nil {
+			body = encoded
+		}
+	}
+	if len(body) > l.maxBodySize {
+		return string(body[:l.maxBodySize]) + "...(truncated)"
+	}
+	return string(body)
+}
+
+// redactJSON replaces the values of redactedBodyKeys and the strings of documents in a decoded JSON value.
+func redactJSON(value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			switch {
+			case redactedBodyKeys[key]:
+				v[key] = redacted
+			case key == "documents":
+				redactDocuments(child)
+			default:
+				redactJSON(child)
+			}
+		}
+	case []interface{}:
+		for _, child := range v {
+			redactJSON(child)
+		}
+	}
+}
+
+// redactDocuments redacts the strings of decoded documents but their IDs.
+func redactDocuments(value interface{}) {
+	documents, _ := value.([]interface{})
+	for i, document := range documents {
+		if fields, ok := document.(map[string]interface{}); ok {
+			id, hasID := fields["id"]
+			redactDocumentStrings(fields)
+			if hasID {
+				fields["id"] = id
+			}
+			continue
+		}
+		documents[i] = redactDocumentStrings(document)
+	}
+}
+
+// redactDocumentStrings replaces the strings of a decoded JSON value, except the values of documentMetadataKeys.
+func redactDocumentStrings(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		return redacted
+	case map[string]interface{}:
+		for key, child := range v {
+			if _, ok := child.(string); ok && documentMetadataKeys[key] {
+				continue
+			}
+			v[key] = redactDocumentStrings(child)
+		}
+	case []interface{}:
+		for i, child := range v {
+			v[i] = redactDocumentStrings(child)
+		}
+	}
+	return value
+}
@@ -0,0 +1,169 @@
+package v20230401_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kde713/azurelangai-go/textanalysis/v20230401"
+)
+
+type logEntry struct {
+	level string
+	msg   string
+	attrs map[string]interface{}
+}
+
+type recordingLogger struct {
+	mu      sync.Mutex
+	entries []logEntry
+}
+
+func (l *recordingLogger) record(level string, msg string, args []interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	attrs := make(map[string]interface{})
+	for i := 0; i+1 < len(args); i += 2 {
+		attrs[args[i].(string)] = args[i+1]
+	}
+	l.entries = append(l.entries, logEntry{level: level, msg: msg, attrs: attrs})
+}
+
+func (l *recordingLogger) DebugContext(_ context.Context, msg string, args ...interface{}) {
+	l.record("DEBUG", msg, args)
+}
+
+func (l *recordingLogger) WarnContext(_ context.Context, msg string, args ...interface{}) {
+	l.record("WARN", msg, args)
+}
+
+func (l *recordingLogger) ErrorContext(_ context.Context, msg string, args ...interface{}) {
+	l.record("ERROR", msg, args)
+}
+
+const confidentialText = "Patient John Doe was admitted on 2023-04-01."
+
+const piiResults = `{"kind":"PiiEntityRecognitionResults","results":{"documents":[{"id":"1","redactedText":"Patient ******** was admitted on **********.","entities":[{"text":"John Doe","category":"Person","offset":8,"length":8,"confidenceScore":0.98}],"warnings":[]}],"errors":[],"modelVersion":"2023-01-01"}}`
+
+func recognizePii(t *testing.T, client v20230401.Client) {
+	_, err := client.AnalyzeTextPiiEntityRecognition(context.TODO(), v20230401.MultiLanguageAnalysisInput{
+		Documents: []v20230401.MultiLanguageInput{{ID: "1", Text: confidentialText, Language: "en"}},
+	}, v20230401.PiiTaskParameters{})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWithLogger(t *testing.T) {
+	srv, _ := newFlakyServer(t, 1, http.StatusServiceUnavailable, nil, piiResults, nil)
+	logger := &recordingLogger{}
+	client := v20230401.NewClient(srv.URL, "secret-key",
+		v20230401.WithLogger(logger, v20230401.WithLogBodies(0)),
+		v20230401.WithRetryCount(1, time.Millisecond, time.Millisecond),
+	)
+
+	recognizePii(t, client)
+
+	if len(logger.entries) != 2 {
+		t.Fatalf("Expected 2 log entries, got %d", len(logger.entries))
+	}
+	failed, succeeded := logger.entries[0], logger.entries[1]
+	if failed.level != "WARN" || failed.attrs["status"] != http.StatusServiceUnavailable || failed.attrs["attempt"] != 1 {
+		t.Errorf("Expected warning for the failed attempt, got %s %v", failed.level, failed.attrs)
+	}
+	if succeeded.level != "DEBUG" || succeeded.attrs["status"] != http.StatusOK || succeeded.attrs["attempt"] != 2 {
+		t.Errorf("Expected debug entry for the retried attempt, got %s %v", succeeded.level, succeeded.attrs)
+	}
+	expected := map[string]interface{}{
+		"method":      http.MethodPost,
+		"path":        v20230401.AnalyzeTextAPIPath,
+		"api_version": v20230401.APIVersion,
+		"request_id":  "request-2",
+	}
+	for key, value := range expected {
+		if succeeded.attrs[key] != value {
+			t.Errorf("Expected %s=%v, got %v", key, value, succeeded.attrs[key])
+		}
+	}
+	if _, ok := succeeded.attrs["duration"].(time.Duration); !ok {
+		t.Errorf("Expected duration, got %v", succeeded.attrs["duration"])
+	}
+
+	requestBody := succeeded.attrs["request_body"].(string)
+	responseBody := succeeded.attrs["response_body"].(string)
+	headers := fmt.Sprint(succeeded.attrs["request_headers"])
+	for _, captured := range []string{requestBody, responseBody, headers} {
+		for _, secret := range []string{confidentialText, "John Doe", "secret-key"} {
+			if strings.Contains(captured, secret) {
+				t.Errorf("Expected %q to be redacted from %s", secret, captured)
+			}
+		}
+	}
+	if !strings.Contains(requestBody, `"kind":"PiiEntityRecognition"`) || !strings.Contains(responseBody, `"category":"Person"`) {
+		t.Errorf("Expected non-confidential fields to be kept, got %s and %s", requestBody, responseBody)
+	}
+}
+
+func TestWithLogger_Unredacted(t *testing.T) {
+	srv, _ := newFlakyServer(t, 1, http.StatusServiceUnavailable, nil, piiResults, nil)
+	logger := &recordingLogger{}
+	client := v20230401.NewClient(srv.URL, "secret-key",
+		v20230401.WithLogger(logger, v20230401.WithLogBodies(0), v20230401.WithLogUnredactedText()),
+		v20230401.WithRetryCount(1, time.Millisecond, time.Millisecond),
+	)
+
+	recognizePii(t, client)
+
+	entry := logger.entries[len(logger.entries)-1]
+	if !strings.Contains(entry.attrs["request_body"].(string), confidentialText) {
+		t.Errorf("Expected document text in request body, got %s", entry.attrs["request_body"])
+	}
+	if strings.Contains(fmt.Sprint(entry.attrs["request_headers"]), "secret-key") {
+		t.Error("Expected subscription key to be redacted")
+	}
+}
+
+func TestWithLogger_NoBodies(t *testing.T) {
+	srv, _ := newFlakyServer(t, 1, http.StatusServiceUnavailable, nil, piiResults, nil)
+	logger := &recordingLogger{}
+	client := v20230401.NewClient(srv.URL, "secret-key",
+		v20230401.WithLogger(logger),
+		v20230401.WithRetryCount(1, time.Millisecond, time.Millisecond),
+	)
+
+	recognizePii(t, client)
+
+	for _, entry := range logger.entries {
+		if _, ok := entry.attrs["request_body"]; ok {
+			t.Errorf("Expected no bodies without WithLogBodies, got %v", entry.attrs)
+		}
+	}
+}
+
+func TestWithLogger_RedactsDocumentStrings(t *testing.T) {
+	const entityLinkingResults = `{"kind":"EntityLinkingResults","results":{"documents":[{"id":"1","entities":[{"name":"John Doe Foundation","matches":[{"text":"Doe Foundation","offset":8,"length":14,"confidenceScore":0.77}],"language":"en","id":"John Doe Foundation","url":"https://en.wikipedia.org/wiki/John_Doe_Foundation","dataSource":"Wikipedia"}],"warnings":[]}],"errors":[],"modelVersion":"2021-06-01"}}`
+	srv, _ := newFlakyServer(t, 0, http.StatusOK, nil, entityLinkingResults, nil)
+	logger := &recordingLogger{}
+	client := v20230401.NewClient(srv.URL, "secret-key", v20230401.WithLogger(logger, v20230401.WithLogBodies(0)))
+
+	_, err := client.AnalyzeTextEntityLinking(context.TODO(), v20230401.MultiLanguageAnalysisInput{
+		Documents: []v20230401.MultiLanguageInput{{ID: "1", Text: "Visited Doe Foundation today.", Language: "en"}},
+	}, v20230401.EntityLinkingTaskParameters{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	responseBody := logger.entries[0].attrs["response_body"].(string)
+	for _, secret := range []string{"John Doe Foundation", "Doe Foundation", "John_Doe_Foundation"} {
+		if strings.Contains(responseBody, secret) {
+			t.Errorf("Expected %q to be redacted from %s", secret, responseBody)
+		}
+	}
+	if !strings.Contains(responseBody, `"id":"1"`) || !strings.Contains(responseBody, `"modelVersion":"2021-06-01"`) {
+		t.Errorf("Expected document IDs and model version to be kept, got %s", responseBody)
+	}
+}
@@ -62,3 +62,51 @@ func (b *LROBuilder) AddExtractiveSummarizationTask(taskName string, parameters
 		TaskName:   taskName,
 	})
 }
+
+func (b *LROBuilder) AddPiiEntityRecognitionTask(taskName string, parameters PiiTaskParameters) {
+	b.body.Tasks = append(b.body.Tasks, TaskRequest{
+		Kind:       TaskKindPiiEntityRecognition,
+		Parameters: parameters,
+		TaskName:   taskName,
+	})
+}
+
+func (b *LROBuilder) AddEntityLinkingTask(taskName string, parameters EntityLinkingTaskParameters) {
+	b.body.Tasks = append(b.body.Tasks, TaskRequest{
+		Kind:       TaskKindEntityLinking,
+		Parameters: parameters,
+		TaskName:   taskName,
+	})
+}
+
+func (b *LROBuilder) AddHealthcareTask(taskName string, parameters HealthcareTaskParameters) {
+	b.body.Tasks = append(b.body.Tasks, TaskRequest{
+		Kind:       TaskKindHealthcare,
+		Parameters: parameters,
+		TaskName:   taskName,
+	})
+}
+
+func (b *LROBuilder) AddCustomEntityRecognitionTask(taskName string, parameters CustomEntitiesTaskParameters) {
+	b.body.Tasks = append(b.body.Tasks, TaskRequest{
+		Kind:       TaskKindCustomEntityRecognition,
+		Parameters: parameters,
+		TaskName:   taskName,
+	})
+}
+
+func (b *LROBuilder) AddCustomSingleLabelClassificationTask(taskName string, parameters CustomSingleLabelClassificationTaskParameters) {
+	b.body.Tasks = append(b.body.Tasks, TaskRequest{
+		Kind:       TaskKindCustomSingleLabelClassification,
+		Parameters: parameters,
+		TaskName:   taskName,
+	})
+}
+
+func (b *LROBuilder) AddCustomMultiLabelClassificationTask(taskName string, parameters CustomMultiLabelClassificationTaskParameters) {
+	b.body.Tasks = append(b.body.Tasks, TaskRequest{
+		Kind:       TaskKindCustomMultiLabelClassification,
+		Parameters: parameters,
+		TaskName:   taskName,
+	})
+}
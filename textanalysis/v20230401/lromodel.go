@@ -16,14 +16,29 @@ const (
 	StatusSucceeded          JobStatus = "succeeded"
 )
 
+// IsTerminal reports whether the job will no longer change its status.
+func (s JobStatus) IsTerminal() bool {
+	switch s {
+	case StatusSucceeded, StatusFailed, StatusPartiallyCompleted, StatusCancelled:
+		return true
+	}
+	return false
+}
+
 type LROKind string
 
 const (
-	LROKindEntityRecognition        LROKind = "EntityRecognitionLROResults"
-	LROKindKeyPhraseExtraction      LROKind = "KeyPhraseExtractionLROResults"
-	LROKindSentimentAnalysis        LROKind = "SentimentAnalysisLROResults"
-	LROKindExtractiveSummarization  LROKind = "ExtractiveSummarizationLROResults"
-	LROKindAbstractiveSummarization LROKind = "AbstractiveSummarizationLROResults"
+	LROKindEntityRecognition               LROKind = "EntityRecognitionLROResults"
+	LROKindKeyPhraseExtraction             LROKind = "KeyPhraseExtractionLROResults"
+	LROKindSentimentAnalysis               LROKind = "SentimentAnalysisLROResults"
+	LROKindExtractiveSummarization         LROKind = "ExtractiveSummarizationLROResults"
+	LROKindAbstractiveSummarization        LROKind = "AbstractiveSummarizationLROResults"
+	LROKindPiiEntityRecognition            LROKind = "PiiEntityRecognitionLROResults"
+	LROKindEntityLinking                   LROKind = "EntityLinkingLROResults"
+	LROKindHealthcare                      LROKind = "HealthcareLROResults"
+	LROKindCustomEntityRecognition         LROKind = "CustomEntityRecognitionLROResults"
+	LROKindCustomSingleLabelClassification LROKind = "CustomSingleLabelClassificationLROResults"
+	LROKindCustomMultiLabelClassification  LROKind = "CustomMultiLabelClassificationLROResults"
 )
 
 type commonLROResult struct {
@@ -95,6 +110,36 @@ func (r *LROResult) UnmarshalJSON(bytes []byte) error {
 			return err
 		}
 		r.Results = results.Results
+	case LROKindPiiEntityRecognition:
+		var results resultsOnly[PiiResult]
+		if err := json.Unmarshal(bytes, &results); err != nil {
+			return err
+		}
+		r.Results = results.Results
+	case LROKindEntityLinking:
+		var results resultsOnly[EntityLinkingResult]
+		if err := json.Unmarshal(bytes, &results); err != nil {
+			return err
+		}
+		r.Results = results.Results
+	case LROKindHealthcare:
+		var results resultsOnly[HealthcareResult]
+		if err := json.Unmarshal(bytes, &results); err != nil {
+			return err
+		}
+		r.Results = results.Results
+	case LROKindCustomEntityRecognition:
+		var results resultsOnly[CustomEntitiesResult]
+		if err := json.Unmarshal(bytes, &results); err != nil {
+			return err
+		}
+		r.Results = results.Results
+	case LROKindCustomSingleLabelClassification, LROKindCustomMultiLabelClassification:
+		var results resultsOnly[CustomLabelClassificationResult]
+		if err := json.Unmarshal(bytes, &results); err != nil {
+			return err
+		}
+		r.Results = results.Results
 	}
 	return nil
 }
@@ -130,4 +175,6 @@ type JobStatusResponse struct {
 	NextLink           string             `json:"nextLink"`
 	Status             JobStatus          `json:"status"`
 	Tasks              Tasks              `json:"tasks"`
+	// Statistics (Optional) If showStats=true was specified in the request this field will contain information about the request payload.
+	Statistics *RequestStatistics `json:"statistics,omitempty"`
 }
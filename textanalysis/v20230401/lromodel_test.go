@@ -0,0 +1,146 @@
+package v20230401_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/kde713/azurelangai-go/textanalysis/v20230401"
+)
+
+func TestLROResult_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		check func(t *testing.T, results interface{})
+	}{
+		{
+			name: "PiiEntityRecognition",
+			input: `{"kind":"PiiEntityRecognitionLROResults","status":"succeeded","taskName":"pii","results":{
+				"documents":[{"id":"1","redactedText":"Call ************.","entities":[{"text":"312-555-1234","category":"PhoneNumber","offset":5,"length":12,"confidenceScore":0.8}],"warnings":[]}],
+				"errors":[],"modelVersion":"2023-01-01"}}`,
+			check: func(t *testing.T, results interface{}) {
+				r, ok := results.(v20230401.PiiResult)
+				if !ok {
+					t.Fatalf("Unexpected result type (failed to decode): %T", results)
+				}
+				if len(r.Documents) != 1 || r.Documents[0].RedactedText != "Call ************." {
+					t.Fatalf("Unexpected documents: %+v", r.Documents)
+				}
+				if len(r.Documents[0].Entities) != 1 || r.Documents[0].Entities[0].Category != "PhoneNumber" {
+					t.Errorf("Unexpected entities: %+v", r.Documents[0].Entities)
+				}
+			},
+		},
+		{
+			name: "EntityLinking",
+			input: `{"kind":"EntityLinkingLROResults","status":"succeeded","taskName":"linking","results":{
+				"documents":[{"id":"1","entities":[{"bingId":"a093e9b9-90f5-a3d5-c4b8-5855e1b01f85","name":"Microsoft","dataSource":"Wikipedia","id":"Microsoft","language":"en","url":"https://en.wikipedia.org/wiki/Microsoft",
+					"matches":[{"text":"Microsoft","offset":0,"length":9,"confidenceScore":0.48}]}],"warnings":[]}],
+				"errors":[],"modelVersion":"2021-06-01"}}`,
+			check: func(t *testing.T, results interface{}) {
+				r, ok := results.(v20230401.EntityLinkingResult)
+				if !ok {
+					t.Fatalf("Unexpected result type (failed to decode): %T", results)
+				}
+				if len(r.Documents) != 1 || len(r.Documents[0].Entities) != 1 {
+					t.Fatalf("Unexpected documents: %+v", r.Documents)
+				}
+				entity := r.Documents[0].Entities[0]
+				if entity.DataSource != "Wikipedia" || entity.URL != "https://en.wikipedia.org/wiki/Microsoft" || entity.BingID == "" {
+					t.Errorf("Unexpected linked entity: %+v", entity)
+				}
+				if len(entity.Matches) != 1 || entity.Matches[0].Length != 9 || entity.Matches[0].ConfidenceScore != 0.48 {
+					t.Errorf("Unexpected matches: %+v", entity.Matches)
+				}
+			},
+		},
+		{
+			name: "Healthcare",
+			input: `{"kind":"HealthcareLROResults","status":"succeeded","taskName":"health","results":{
+				"documents":[{"id":"1","entities":[
+					{"offset":25,"length":5,"text":"100mg","category":"Dosage","confidenceScore":1.0},
+					{"offset":31,"length":10,"text":"ibuprofen","category":"MedicationName","confidenceScore":1.0,"name":"ibuprofen",
+						"assertion":{"certainty":"negative"},"links":[{"dataSource":"UMLS","id":"C0020740"}]}],
+					"relations":[{"relationType":"DosageOfMedication","confidenceScore":1.0,"entities":[{"ref":"#/results/documents/0/entities/0","role":"Dosage"},{"ref":"#/results/documents/0/entities/1","role":"Medication"}]}],
+					"fhirBundle":{"resourceType":"Bundle","id":"b1","type":"document","entry":[{"fullUrl":"Composition/c1","resource":{"resourceType":"Composition","id":"c1"}}]},
+					"warnings":[]}],
+				"errors":[],"modelVersion":"2023-04-15"}}`,
+			check: func(t *testing.T, results interface{}) {
+				r, ok := results.(v20230401.HealthcareResult)
+				if !ok {
+					t.Fatalf("Unexpected result type (failed to decode): %T", results)
+				}
+				if len(r.Documents) != 1 || len(r.Documents[0].Entities) != 2 {
+					t.Fatalf("Unexpected documents: %+v", r.Documents)
+				}
+				doc := r.Documents[0]
+				if doc.Entities[1].Assertion == nil || doc.Entities[1].Assertion.Certainty != "negative" {
+					t.Errorf("Unexpected assertion: %+v", doc.Entities[1].Assertion)
+				}
+				if len(doc.Entities[1].Links) != 1 || doc.Entities[1].Links[0].DataSource != "UMLS" {
+					t.Errorf("Unexpected links: %+v", doc.Entities[1].Links)
+				}
+				if len(doc.Relations) != 1 || doc.Relations[0].Entities[1].Role != "Medication" {
+					t.Errorf("Unexpected relations: %+v", doc.Relations)
+				}
+				bundle, err := doc.DecodeFhirBundle()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if bundle.ResourceType != "Bundle" || len(bundle.Entry) != 1 {
+					t.Fatalf("Unexpected bundle: %+v", bundle)
+				}
+				if resourceType, err := bundle.Entry[0].ResourceType(); err != nil || resourceType != "Composition" {
+					t.Errorf("Expected Composition resource, got %q (%v)", resourceType, err)
+				}
+			},
+		},
+		{
+			name: "CustomEntityRecognition",
+			input: `{"kind":"CustomEntityRecognitionLROResults","status":"succeeded","taskName":"custom-ner","results":{
+				"projectName":"loan-agreements","deploymentName":"production",
+				"documents":[{"id":"1","entities":[{"text":"John Doe","category":"BorrowerName","offset":12,"length":8,"confidenceScore":0.99}],"warnings":[]}],
+				"errors":[]}}`,
+			check: func(t *testing.T, results interface{}) {
+				r, ok := results.(v20230401.CustomEntitiesResult)
+				if !ok {
+					t.Fatalf("Unexpected result type (failed to decode): %T", results)
+				}
+				if r.ProjectName != "loan-agreements" || r.DeploymentName != "production" {
+					t.Errorf("Unexpected project: %s/%s", r.ProjectName, r.DeploymentName)
+				}
+				if len(r.Documents) != 1 || len(r.Documents[0].Entities) != 1 || r.Documents[0].Entities[0].Category != "BorrowerName" {
+					t.Errorf("Unexpected documents: %+v", r.Documents)
+				}
+			},
+		},
+		{
+			name: "CustomMultiLabelClassification",
+			input: `{"kind":"CustomMultiLabelClassificationLROResults","status":"succeeded","taskName":"custom-mlc","results":{
+				"projectName":"movies","deploymentName":"production",
+				"documents":[{"id":"1","class":[{"category":"Action","confidenceScore":0.91},{"category":"Comedy","confidenceScore":0.62}],"warnings":[]}],
+				"errors":[]}}`,
+			check: func(t *testing.T, results interface{}) {
+				r, ok := results.(v20230401.CustomLabelClassificationResult)
+				if !ok {
+					t.Fatalf("Unexpected result type (failed to decode): %T", results)
+				}
+				if len(r.Documents) != 1 || len(r.Documents[0].Class) != 2 || r.Documents[0].Class[1].Category != "Comedy" {
+					t.Errorf("Unexpected documents: %+v", r.Documents)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var result v20230401.LROResult
+			if err := json.Unmarshal([]byte(tt.input), &result); err != nil {
+				t.Fatal(err)
+			}
+			if result.Status != v20230401.StatusSucceeded {
+				t.Fatalf("Unexpected status: %s", result.Status)
+			}
+			tt.check(t, result.Results)
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package v20230401
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// ResponseMetadata is the service metadata of a response.
+type ResponseMetadata struct {
+	// StatusCode HTTP status of the response.
+	StatusCode int
+	// RequestID Value of the apim-request-id header, which Azure support asks for.
+	RequestID string
+	// UpstreamServiceTime Processing time reported by the service in x-envoy-upstream-service-time.
+	UpstreamServiceTime time.Duration
+	// BillingUsage Billed units by meter from csp-billing-usage, e.g. "CognitiveServices.TextAnalytics.TextRecords".
+	BillingUsage map[string]int
+	// RetryAfter Delay requested with Retry-After, retry-after-ms or x-ms-retry-after-ms on throttled responses.
+	RetryAfter time.Duration
+	// Header All response headers.
+	Header http.Header
+}
+
+func newResponseMetadata(resp *resty.Response) ResponseMetadata {
+	header := resp.Header()
+	metadata := ResponseMetadata{
+		StatusCode: resp.StatusCode(),
+		RequestID:  header.Get("apim-request-id"),
+		RetryAfter: parseRetryAfter(header, time.Now()),
+		Header:     header.Clone(),
+	}
+	if ms, err := strconv.ParseInt(header.Get("x-envoy-upstream-service-time"), 10, 64); err == nil {
+		metadata.UpstreamServiceTime = time.Duration(ms) * time.Millisecond
+	}
+	if usage := header.Get("csp-billing-usage"); usage != "" {
+		metadata.BillingUsage = make(map[string]int)
+		for _, meter := range strings.Split(usage, ",") {
+			name, value, ok := strings.Cut(strings.TrimSpace(meter), "=")
+			if !ok {
+				continue
+			}
+			if units, err := strconv.Atoi(value); err == nil {
+				metadata.BillingUsage[name] = units
+			}
+		}
+	}
+	return metadata
+}
+
+type responseCaptureKey struct{}
+
+// ResponseCapture collects the metadata of the responses received for calls made with its context.
+// It is safe for concurrent use.
+type ResponseCapture struct {
+	mu        sync.Mutex
+	responses []ResponseMetadata
+}
+
+// CaptureResponses returns a context that captures the metadata of every response received for Client calls made
+// with it, including retried attempts:
+//
+//	ctx, capture := v20230401.CaptureResponses(ctx)
+//	result, err := client.AnalyzeTextSentimentAnalysis(ctx, input, parameters)
+//	log.Printf("apim-request-id: %s", capture.Last().RequestID)
+func CaptureResponses(ctx context.Context) (context.Context, *ResponseCapture) {
+	capture := &ResponseCapture{}
+	return context.WithValue(ctx, responseCaptureKey{}, capture), capture
+}
+
+func responseCaptureFromContext(ctx context.Context) *ResponseCapture {
+	capture, _ := ctx.Value(responseCaptureKey{}).(*ResponseCapture)
+	return capture
+}
+
+func (c *ResponseCapture) add(resp *resty.Response) {
+	if c == nil || resp == nil || resp.RawResponse == nil {
+		return
+	}
+	metadata := newResponseMetadata(resp)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.responses = append(c.responses, metadata)
+}
+
+// Last returns the metadata of the last response received, or a zero ResponseMetadata if there is none.
+func (c *ResponseCapture) Last() ResponseMetadata {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.responses) == 0 {
+		return ResponseMetadata{}
+	}
+	return c.responses[len(c.responses)-1]
+}
+
+// All returns the metadata of every response received, in order.
+func (c *ResponseCapture) All() []ResponseMetadata {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]ResponseMetadata(nil), c.responses...)
+}
@@ -0,0 +1,101 @@
+package v20230401_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kde713/azurelangai-go/textanalysis/v20230401"
+)
+
+func TestCaptureResponses(t *testing.T) {
+	srv, _ := newFlakyServer(t, 1, http.StatusTooManyRequests, http.Header{"Retry-After-Ms": {"5"}},
+		`{"kind":"KeyPhraseExtractionResults","results":{"documents":[],"errors":[],"modelVersion":"2022-10-01"}}`,
+		http.Header{
+			"X-Envoy-Upstream-Service-Time": {"42"},
+			"Csp-Billing-Usage":             {"CognitiveServices.TextAnalytics.BatchScoring=1,CognitiveServices.TextAnalytics.TextRecords=3"},
+		})
+	client := v20230401.NewClient(srv.URL, "key", v20230401.WithRetryCount(1, time.Millisecond, 10*time.Millisecond))
+
+	ctx, capture := v20230401.CaptureResponses(context.Background())
+	if _, err := analyzeKeyPhrases(ctx, client); err != nil {
+		t.Fatal(err)
+	}
+
+	responses := capture.All()
+	if len(responses) != 2 {
+		t.Fatalf("Expected 2 responses, got %d", len(responses))
+	}
+	throttled := responses[0]
+	if throttled.StatusCode != http.StatusTooManyRequests || throttled.RequestID != "request-1" || throttled.RetryAfter != 5*time.Millisecond {
+		t.Errorf("Unexpected metadata of the throttled response: %+v", throttled)
+	}
+	last := capture.Last()
+	if last.StatusCode != http.StatusOK || last.RequestID != "request-2" {
+		t.Errorf("Expected status 200 and request-2, got %d and %s", last.StatusCode, last.RequestID)
+	}
+	if last.UpstreamServiceTime != 42*time.Millisecond {
+		t.Errorf("Expected upstream service time 42ms, got %s", last.UpstreamServiceTime)
+	}
+	if got := last.BillingUsage["CognitiveServices.TextAnalytics.TextRecords"]; got != 3 {
+		t.Errorf("Expected 3 billed text records, got %d", got)
+	}
+	if got := last.Header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("Expected response headers, got Content-Type %q", got)
+	}
+}
+
+func TestTaskError_Metadata(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("apim-request-id", "request-1")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":{"code":"InvalidRequest","message":"Invalid Request."}}`))
+	}))
+	defer srv.Close()
+	client := v20230401.NewClient(srv.URL, "key")
+
+	_, err := analyzeKeyPhrases(context.Background(), client)
+	var taskErr *v20230401.TaskError
+	if !errors.As(err, &taskErr) {
+		t.Fatalf("Expected TaskError, got %v", err)
+	}
+	if taskErr.StatusCode != http.StatusBadRequest || taskErr.RequestID != "request-1" {
+		t.Errorf("Expected status 400 and request-1, got %d and %s", taskErr.StatusCode, taskErr.RequestID)
+	}
+	if !strings.Contains(err.Error(), "request-1") {
+		t.Errorf("Expected request ID in error message, got %s", err)
+	}
+}
+
+func TestCaptureResponses_KeyRotation(t *testing.T) {
+	var validKey atomic.Value
+	validKey.Store("secondary")
+	srv, _ := newKeyCheckingServer(t, &validKey)
+	logger := &recordingLogger{}
+	sink := &recordingSink{}
+	client := v20230401.NewClientWithKeyProvider(srv.URL, v20230401.NewKeyPair("primary", "secondary"),
+		v20230401.WithLogger(logger), v20230401.WithMetrics(sink))
+
+	ctx, capture := v20230401.CaptureResponses(context.Background())
+	if _, err := client.GetTextAnalyticsJobResult(ctx, "job-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	responses := capture.All()
+	if len(responses) != 2 || responses[0].StatusCode != http.StatusUnauthorized || responses[1].StatusCode != http.StatusOK {
+		t.Errorf("Expected the rejected and the accepted response, got %+v", responses)
+	}
+	if len(logger.entries) != 2 || logger.entries[0].attrs["status"] != http.StatusUnauthorized {
+		t.Errorf("Expected the rejected response to be logged, got %+v", logger.entries)
+	}
+	// The resend with the other key is not a retry.
+	if len(sink.calls) != 1 || sink.calls[0].Attempts != 1 || sink.calls[0].StatusCode != http.StatusOK {
+		t.Errorf("Expected 1 call with 1 attempt, got %+v", sink.calls)
+	}
+}
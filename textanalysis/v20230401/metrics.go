@@ -0,0 +1,175 @@
+package v20230401
+
+import (
+	"expvar"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// CallMetrics describes a completed Client call.
+type CallMetrics struct {
+	// Operation Operation of the call, e.g. OperationAnalyzeText.
+	Operation string
+	// TaskKind Task kind of analyze-text calls.
+	TaskKind TaskKind
+	// StatusCode HTTP status of the last attempt, or zero if it got no response.
+	StatusCode int
+	// RequestID apim-request-id of the last response.
+	RequestID string
+	// Duration Duration of the call including retries.
+	Duration time.Duration
+	// Attempts Number of attempts made by the retry policy. A request resent with another key or token after 401
+	// Unauthorized is not another attempt.
+	Attempts int
+	// Throttles Number of 429 Too Many Requests and 503 Service Unavailable responses.
+	Throttles int
+	// Documents Number of documents sent.
+	Documents int
+	// Characters Number of characters (Unicode code points) of the documents sent.
+	Characters int
+	// DocumentErrors Number of document errors in the result.
+	DocumentErrors int
+	// Err Error returned by the call.
+	Err error
+}
+
+// Retries returns the number of retries of the call.
+func (m CallMetrics) Retries() int {
+	if m.Attempts == 0 {
+		return 0
+	}
+	return m.Attempts - 1
+}
+
+// MetricsSink receives the metrics of every Client call, e.g. to export them to Prometheus or StatsD.
+// Implementations must be safe for concurrent use and should not block.
+type MetricsSink interface {
+	RecordCall(m CallMetrics)
+}
+
+// expvarPublishMu serializes Publish, so that checking and publishing a name is atomic.
+var expvarPublishMu sync.Mutex
+
+// DefaultLatencyBuckets are the upper bounds of the latency histogram of ExpvarMetrics.
+var DefaultLatencyBuckets = []time.Duration{
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	1 * time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// ExpvarMetrics is a MetricsSink that exposes metrics with the expvar package:
+//
+//	requests        calls by "operation/taskKind/statusCode" (status 0 on transport errors)
+//	latency_ms      per-operation histogram with cumulative "le_<ms>" and "le_inf" buckets, "count" and "sum"
+//	retries         retries
+//	throttles       429 Too Many Requests and 503 Service Unavailable responses
+//	documents       documents sent
+//	characters      characters sent
+//	document_errors document errors received
+//	errors          failed calls
+type ExpvarMetrics struct {
+	root           *expvar.Map
+	requests       *expvar.Map
+	latency        *expvar.Map
+	retries        *expvar.Int
+	throttles      *expvar.Int
+	documents      *expvar.Int
+	characters     *expvar.Int
+	documentErrors *expvar.Int
+	errors         *expvar.Int
+	buckets        []time.Duration
+
+	mu         sync.Mutex
+	histograms map[string]*expvar.Map
+}
+
+var _ MetricsSink = (*ExpvarMetrics)(nil)
+
+// NewExpvarMetrics creates an ExpvarMetrics and publishes it under name, e.g. "azurelangai", which is served on
+// /debug/vars. An empty name doesn't publish it. Like expvar.Publish, it panics if name is already in use, e.g. when
+// called twice with the same name; use Publish to get an error instead.
+func NewExpvarMetrics(name string) *ExpvarMetrics {
+	m := &ExpvarMetrics{
+		root:           new(expvar.Map).Init(),
+		requests:       new(expvar.Map).Init(),
+		latency:        new(expvar.Map).Init(),
+		retries:        new(expvar.Int),
+		throttles:      new(expvar.Int),
+		documents:      new(expvar.Int),
+		characters:     new(expvar.Int),
+		documentErrors: new(expvar.Int),
+		errors:         new(expvar.Int),
+		buckets:        DefaultLatencyBuckets,
+		histograms:     make(map[string]*expvar.Map),
+	}
+	m.root.Set("requests", m.requests)
+	m.root.Set("latency_ms", m.latency)
+	m.root.Set("retries", m.retries)
+	m.root.Set("throttles", m.throttles)
+	m.root.Set("documents", m.documents)
+	m.root.Set("characters", m.characters)
+	m.root.Set("document_errors", m.documentErrors)
+	m.root.Set("errors", m.errors)
+	if name != "" {
+		expvar.Publish(name, m.root)
+	}
+	return m
+}
+
+// Publish publishes the metrics under name. Unlike NewExpvarMetrics, it returns an error if name is already in use.
+func (m *ExpvarMetrics) Publish(name string) error {
+	expvarPublishMu.Lock()
+	defer expvarPublishMu.Unlock()
+	if expvar.Get(name) != nil {
+		return fmt.Errorf("expvar name %q is already in use", name)
+	}
+	expvar.Publish(name, m.root)
+	return nil
+}
+
+// Map returns the published map of the metrics.
+func (m *ExpvarMetrics) Map() *expvar.Map {
+	return m.root
+}
+
+func (m *ExpvarMetrics) RecordCall(call CallMetrics) {
+	m.requests.Add(call.Operation+"/"+string(call.TaskKind)+"/"+strconv.Itoa(call.StatusCode), 1)
+	m.retries.Add(int64(call.Retries()))
+	m.throttles.Add(int64(call.Throttles))
+	m.documents.Add(int64(call.Documents))
+	m.characters.Add(int64(call.Characters))
+	m.documentErrors.Add(int64(call.DocumentErrors))
+	if call.Err != nil {
+		m.errors.Add(1)
+	}
+
+	histogram := m.histogram(call.Operation)
+	for _, bucket := range m.buckets {
+		if call.Duration <= bucket {
+			histogram.Add("le_"+strconv.FormatInt(bucket.Milliseconds(), 10), 1)
+		}
+	}
+	histogram.Add("le_inf", 1)
+	histogram.Add("count", 1)
+	histogram.AddFloat("sum", float64(call.Duration)/float64(time.Millisecond))
+}
+
+func (m *ExpvarMetrics) histogram(operation string) *expvar.Map {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	histogram, ok := m.histograms[operation]
+	if !ok {
+		histogram = new(expvar.Map).Init()
+		m.histograms[operation] = histogram
+		m.latency.Set(operation, histogram)
+	}
+	return histogram
+}
@@ -0,0 +1,138 @@
+package v20230401_test
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kde713/azurelangai-go/textanalysis/v20230401"
+)
+
+type recordingSink struct {
+	mu    sync.Mutex
+	calls []v20230401.CallMetrics
+}
+
+func (s *recordingSink) RecordCall(m v20230401.CallMetrics) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = append(s.calls, m)
+}
+
+const keyPhraseResultsWithError = `{"kind":"KeyPhraseExtractionResults","results":{"documents":[{"id":"1","keyPhrases":[],"warnings":[]}],"errors":[{"id":"2","error":{"code":"InvalidArgument","message":"Document text is empty."}}],"modelVersion":"2022-10-01"}}`
+
+func analyzeTestDocuments(t *testing.T, client v20230401.Client) {
+	_, err := client.AnalyzeTextKeyPhraseExtraction(context.TODO(), v20230401.MultiLanguageAnalysisInput{
+		Documents: []v20230401.MultiLanguageInput{{ID: "1", Text: "안녕하세요"}, {ID: "2", Text: ""}},
+	}, v20230401.KeyPhraseTaskParameters{})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWithMetrics(t *testing.T) {
+	srv, _ := newFlakyServer(t, 1, http.StatusTooManyRequests, nil, keyPhraseResultsWithError, nil)
+	sink := &recordingSink{}
+	client := v20230401.NewClient(srv.URL, "key",
+		v20230401.WithMetrics(sink),
+		v20230401.WithRetryCount(1, time.Millisecond, time.Millisecond),
+	)
+
+	analyzeTestDocuments(t, client)
+
+	if len(sink.calls) != 1 {
+		t.Fatalf("Expected 1 call, got %d", len(sink.calls))
+	}
+	m := sink.calls[0]
+	if m.Operation != v20230401.OperationAnalyzeText || m.TaskKind != v20230401.TaskKindKeyPhraseExtraction {
+		t.Errorf("Expected AnalyzeText of KeyPhraseExtraction, got %s of %s", m.Operation, m.TaskKind)
+	}
+	if m.StatusCode != http.StatusOK || m.RequestID != "request-2" {
+		t.Errorf("Expected status 200 and request-2, got %d and %s", m.StatusCode, m.RequestID)
+	}
+	if m.Attempts != 2 || m.Retries() != 1 || m.Throttles != 1 {
+		t.Errorf("Expected 2 attempts with 1 throttle, got %+v", m)
+	}
+	if m.Documents != 2 || m.Characters != 5 || m.DocumentErrors != 1 {
+		t.Errorf("Expected 2 documents, 5 characters and 1 document error, got %+v", m)
+	}
+	if m.Duration <= 0 || m.Err != nil {
+		t.Errorf("Expected successful call with duration, got %+v", m)
+	}
+}
+
+func TestWithMetrics_ServiceUnavailable(t *testing.T) {
+	srv, _ := newFlakyServer(t, 1, http.StatusServiceUnavailable, nil, keyPhraseResultsWithError, nil)
+	sink := &recordingSink{}
+	client := v20230401.NewClient(srv.URL, "key",
+		v20230401.WithMetrics(sink),
+		v20230401.WithRetryCount(1, time.Millisecond, time.Millisecond),
+	)
+
+	analyzeTestDocuments(t, client)
+
+	if len(sink.calls) != 1 {
+		t.Fatalf("Expected 1 call, got %d", len(sink.calls))
+	}
+	if m := sink.calls[0]; m.Attempts != 2 || m.Throttles != 1 {
+		t.Errorf("Expected 2 attempts with 1 throttle, got %+v", m)
+	}
+}
+
+func TestExpvarMetrics(t *testing.T) {
+	srv, _ := newFlakyServer(t, 1, http.StatusTooManyRequests, nil, keyPhraseResultsWithError, nil)
+	// expvar names can't be reused, e.g. by go test -count=2.
+	name := fmt.Sprintf("azurelangai_test_%d", time.Now().UnixNano())
+	metrics := v20230401.NewExpvarMetrics(name)
+	client := v20230401.NewClient(srv.URL, "key",
+		v20230401.WithMetrics(metrics),
+		v20230401.WithRetryCount(1, time.Millisecond, time.Millisecond),
+	)
+
+	analyzeTestDocuments(t, client)
+	analyzeTestDocuments(t, client)
+
+	if expvar.Get(name) != metrics.Map() {
+		t.Error("Expected metrics to be published")
+	}
+	intValue := func(name string) int64 {
+		return metrics.Map().Get(name).(*expvar.Int).Value()
+	}
+	for name, expected := range map[string]int64{
+		"retries":         1,
+		"throttles":       1,
+		"documents":       4,
+		"characters":      10,
+		"document_errors": 2,
+		"errors":          0,
+	} {
+		if got := intValue(name); got != expected {
+			t.Errorf("Expected %s=%d, got %d", name, expected, got)
+		}
+	}
+	requests := metrics.Map().Get("requests").(*expvar.Map)
+	if got := requests.Get("AnalyzeText/KeyPhraseExtraction/200"); got == nil || got.(*expvar.Int).Value() != 2 {
+		t.Errorf("Expected 2 successful requests, got %v", got)
+	}
+	histogram := metrics.Map().Get("latency_ms").(*expvar.Map).Get(v20230401.OperationAnalyzeText).(*expvar.Map)
+	if got := histogram.Get("count").(*expvar.Int).Value(); got != 2 {
+		t.Errorf("Expected 2 latency observations, got %d", got)
+	}
+	if got := histogram.Get("le_inf").(*expvar.Int).Value(); got != 2 {
+		t.Errorf("Expected 2 observations in le_inf, got %d", got)
+	}
+}
+
+func TestExpvarMetrics_Publish(t *testing.T) {
+	name := fmt.Sprintf("azurelangai_test_publish_%d", time.Now().UnixNano())
+	if err := v20230401.NewExpvarMetrics("").Publish(name); err != nil {
+		t.Fatal(err)
+	}
+	if err := v20230401.NewExpvarMetrics("").Publish(name); err == nil {
+		t.Error("Expected error when publishing a name in use")
+	}
+}
@@ -1,5 +1,7 @@
 package v20230401
 
+import "encoding/json"
+
 type RequestBody[AnalysisInput any, Parameters any] struct {
 	// Kind Enumeration of supported Text Analysis tasks.
 	Kind          TaskKind      `json:"kind"`
@@ -42,7 +44,14 @@ type EntitiesTaskParameters struct {
 	LoggingOptOut bool   `json:"loggingOptOut,omitempty"`
 	ModelVersion  string `json:"modelVersion,omitempty"`
 	// StringIndexType Specifies the method used to interpret string offsets. Defaults to Text Elements (Graphemes) according to Unicode v8.0.0. For additional information see https://aka.ms/text-analytics-offsets.
-	StringIndexType string `json:"stringIndexType,omitempty"`
+	StringIndexType StringIndexType `json:"stringIndexType,omitempty"`
+}
+
+type EntityLinkingTaskParameters struct {
+	LoggingOptOut bool   `json:"loggingOptOut,omitempty"`
+	ModelVersion  string `json:"modelVersion,omitempty"`
+	// StringIndexType Specifies the method used to interpret string offsets. Defaults to Text Elements (Graphemes) according to Unicode v8.0.0. For additional information see https://aka.ms/text-analytics-offsets.
+	StringIndexType StringIndexType `json:"stringIndexType,omitempty"`
 }
 
 type KeyPhraseTaskParameters struct {
@@ -55,7 +64,7 @@ type SentimentAnalysisTaskParameters struct {
 	ModelVersion  string `json:"modelVersion,omitempty"`
 	OpinionMining bool   `json:"opinionMining,omitempty"`
 	// StringIndexType Specifies the method used to interpret string offsets. Defaults to Text Elements (Graphemes) according to Unicode v8.0.0. For additional information see https://aka.ms/text-analytics-offsets.
-	StringIndexType string `json:"stringIndexType,omitempty"`
+	StringIndexType StringIndexType `json:"stringIndexType,omitempty"`
 }
 
 type ExtractiveSummarizationTaskParameters struct {
@@ -67,7 +76,7 @@ type ExtractiveSummarizationTaskParameters struct {
 	// "Rank": Indicates that results should be sorted in order of importance (i.e. rank score) according to the model.
 	SortBy string `json:"sortBy,omitempty"`
 	// StringIndexType Specifies the method used to interpret string offsets. Defaults to Text Elements (Graphemes) according to Unicode v8.0.0. For additional information see https://aka.ms/text-analytics-offsets.
-	StringIndexType string `json:"stringIndexType,omitempty"`
+	StringIndexType StringIndexType `json:"stringIndexType,omitempty"`
 }
 
 type AbstractiveSummarizationTaskParameters struct {
@@ -75,7 +84,55 @@ type AbstractiveSummarizationTaskParameters struct {
 	ModelVersion  string `json:"modelVersion,omitempty"`
 	SentenceCount int    `json:"sentenceCount,omitempty"`
 	// StringIndexType Specifies the method used to interpret string offsets. Defaults to Text Elements (Graphemes) according to Unicode v8.0.0. For additional information see https://aka.ms/text-analytics-offsets.
-	StringIndexType string `json:"stringIndexType,omitempty"`
+	StringIndexType StringIndexType `json:"stringIndexType,omitempty"`
+}
+
+type PiiTaskParameters struct {
+	// Domain (Optional) The PII domain used for PII Entity Recognition. Defaults to "none".
+	Domain        PiiDomain `json:"domain,omitempty"`
+	LoggingOptOut bool      `json:"loggingOptOut,omitempty"`
+	ModelVersion  string    `json:"modelVersion,omitempty"`
+	// PiiCategories (Optional) Describes the PII categories to return.
+	PiiCategories []PiiCategory `json:"piiCategories,omitempty"`
+	// StringIndexType Specifies the method used to interpret string offsets. Defaults to Text Elements (Graphemes) according to Unicode v8.0.0. For additional information see https://aka.ms/text-analytics-offsets.
+	StringIndexType StringIndexType `json:"stringIndexType,omitempty"`
+}
+
+type HealthcareTaskParameters struct {
+	// DocumentType (Optional) Document type that can be provided as input for Fhir Documents. Expect to have FhirVersion provided when used. Behavior of using None enum is the same as not using the DocumentType parameter.
+	DocumentType HealthcareDocumentType `json:"documentType,omitempty"`
+	// FhirVersion (Optional) The FHIR Spec version that the result will use to format the FhirBundle. For additional information see https://www.hl7.org/fhir/overview.html.
+	FhirVersion   string `json:"fhirVersion,omitempty"`
+	LoggingOptOut bool   `json:"loggingOptOut,omitempty"`
+	ModelVersion  string `json:"modelVersion,omitempty"`
+	// StringIndexType Specifies the method used to interpret string offsets. Defaults to Text Elements (Graphemes) according to Unicode v8.0.0. For additional information see https://aka.ms/text-analytics-offsets.
+	StringIndexType StringIndexType `json:"stringIndexType,omitempty"`
+}
+
+type CustomEntitiesTaskParameters struct {
+	// DeploymentName This field indicates the deployment name for the model.
+	DeploymentName string `json:"deploymentName"`
+	LoggingOptOut  bool   `json:"loggingOptOut,omitempty"`
+	// ProjectName This field indicates the project name for the model.
+	ProjectName string `json:"projectName"`
+	// StringIndexType Specifies the method used to interpret string offsets. Defaults to Text Elements (Graphemes) according to Unicode v8.0.0. For additional information see https://aka.ms/text-analytics-offsets.
+	StringIndexType StringIndexType `json:"stringIndexType,omitempty"`
+}
+
+type CustomSingleLabelClassificationTaskParameters struct {
+	// DeploymentName This field indicates the deployment name for the model.
+	DeploymentName string `json:"deploymentName"`
+	LoggingOptOut  bool   `json:"loggingOptOut,omitempty"`
+	// ProjectName This field indicates the project name for the model.
+	ProjectName string `json:"projectName"`
+}
+
+type CustomMultiLabelClassificationTaskParameters struct {
+	// DeploymentName This field indicates the deployment name for the model.
+	DeploymentName string `json:"deploymentName"`
+	LoggingOptOut  bool   `json:"loggingOptOut,omitempty"`
+	// ProjectName This field indicates the project name for the model.
+	ProjectName string `json:"projectName"`
 }
 
 type InputError struct {
@@ -87,22 +144,57 @@ type InputError struct {
 
 type ErrorInformation struct {
 	// Code One of a server-defined set of error codes.
-	Code string `json:"code"`
+	Code ErrorCode `json:"code"`
 	// Message A human-readable representation of the error.
 	Message string `json:"message"`
 	// Target The target of the error.
 	Target string `json:"target"`
+	// Details An array of details about specific errors that led to this reported error.
+	Details []ErrorInformation `json:"details,omitempty"`
+	// InnerError An object containing more specific information than the current object about the error.
+	InnerError *InnerError `json:"innererror,omitempty"`
+}
+
+type InnerError struct {
+	// Code One of a server-defined set of error codes.
+	Code InnerErrorCode `json:"code"`
+	// Message Error message.
+	Message string `json:"message"`
+	// Details Error details.
+	Details map[string]string `json:"details,omitempty"`
+	// Target Error target.
+	Target string `json:"target,omitempty"`
+	// InnerError An object containing more specific information than the current object about the error.
+	InnerError *InnerError `json:"innererror,omitempty"`
 }
 
 type DocumentWarning struct {
 	// Code Error code.
-	Code string `json:"code"`
+	Code WarningCode `json:"code"`
 	// Message Warning message.
 	Message string `json:"message"`
 	// TargetRef A JSON pointer reference indicating the target object.
 	TargetRef string `json:"targetRef"`
 }
 
+type DocumentStatistics struct {
+	// CharactersCount Number of text elements recognized in the document.
+	CharactersCount int `json:"charactersCount"`
+	// TransactionsCount Number of transactions for the document.
+	TransactionsCount int `json:"transactionsCount"`
+}
+
+type RequestStatistics struct {
+	// DocumentsCount Number of documents submitted in the request.
+	DocumentsCount int `json:"documentsCount"`
+	// ErroneousDocumentsCount Number of invalid documents. This includes empty, over-size limit or non-supported languages documents.
+	ErroneousDocumentsCount int `json:"erroneousDocumentsCount"`
+	// TransactionsCount Number of transactions for the request.
+	TransactionsCount int `json:"transactionsCount"`
+	// ValidDocumentsCount Number of valid documents. This excludes empty, over-size limit or non-supported languages documents.
+	ValidDocumentsCount int `json:"validDocumentsCount"`
+}
+
 type ErrorResponse struct {
 	// Error The error object.
 	Error ErrorInformation `json:"error"`
@@ -130,6 +222,8 @@ type LanguageDetectionDocumentResult struct {
 	DetectedLanguage DetectedLanguage `json:"detectedLanguage"`
 	// ID Unique, non-empty document identifier.
 	ID string `json:"id"`
+	// Statistics (Optional) If showStats=true was specified in the request this field will contain information about the document payload.
+	Statistics *DocumentStatistics `json:"statistics,omitempty"`
 	// Warnings Warnings encountered while processing document.
 	Warnings []DocumentWarning `json:"warnings"`
 }
@@ -141,6 +235,8 @@ type LanguageDetectionResult struct {
 	Errors []InputError `json:"errors"`
 	// ModelVersion This field indicates which model is used for scoring.
 	ModelVersion string `json:"modelVersion"`
+	// Statistics (Optional) If showStats=true was specified in the request this field will contain information about the request payload.
+	Statistics *RequestStatistics `json:"statistics,omitempty"`
 }
 
 type DocumentError struct {
@@ -170,6 +266,60 @@ type EntityRecognizedDocument struct {
 	Entities []Entity `json:"entities"`
 	// ID Unique, non-empty document identifier.
 	ID string `json:"id"`
+	// Statistics (Optional) If showStats=true was specified in the request this field will contain information about the document payload.
+	Statistics *DocumentStatistics `json:"statistics,omitempty"`
+	// Warnings Warnings encountered while processing document.
+	Warnings []DocumentWarning `json:"warnings"`
+}
+
+type Match struct {
+	// ConfidenceScore If a well known item is recognized, a decimal number denoting the confidence level between 0 and 1 will be returned.
+	ConfidenceScore float64 `json:"confidenceScore"`
+	// Length Length for the entity match text.
+	Length int `json:"length"`
+	// Offset Start position for the entity match text.
+	Offset int `json:"offset"`
+	// Text Entity text as appears in the request.
+	Text string `json:"text"`
+}
+
+type LinkedEntity struct {
+	// BingID Bing Entity Search API unique identifier of the recognized entity.
+	BingID string `json:"bingId"`
+	// DataSource Data source used to extract entity linking, such as Wiki/Bing etc.
+	DataSource string `json:"dataSource"`
+	// ID Unique identifier of the recognized entity from the data source.
+	ID string `json:"id"`
+	// Language Language used in the data source.
+	Language string `json:"language"`
+	// Matches List of instances this entity appears in the text.
+	Matches []Match `json:"matches"`
+	// Name Entity Linking formal name.
+	Name string `json:"name"`
+	// URL URL for the entity's page from the data source.
+	URL string `json:"url"`
+}
+
+type LinkedEntitiesDocumentResult struct {
+	// Entities Recognized well known entities in the document.
+	Entities []LinkedEntity `json:"entities"`
+	// ID Unique, non-empty document identifier.
+	ID string `json:"id"`
+	// Statistics (Optional) If showStats=true was specified in the request this field will contain information about the document payload.
+	Statistics *DocumentStatistics `json:"statistics,omitempty"`
+	// Warnings Warnings encountered while processing document.
+	Warnings []DocumentWarning `json:"warnings"`
+}
+
+type PiiEntitiesDocumentResult struct {
+	// Entities Recognized PII entities in the document.
+	Entities []Entity `json:"entities"`
+	// ID Unique, non-empty document identifier.
+	ID string `json:"id"`
+	// RedactedText Returns redacted text.
+	RedactedText string `json:"redactedText"`
+	// Statistics (Optional) If showStats=true was specified in the request this field will contain information about the document payload.
+	Statistics *DocumentStatistics `json:"statistics,omitempty"`
 	// Warnings Warnings encountered while processing document.
 	Warnings []DocumentWarning `json:"warnings"`
 }
@@ -177,6 +327,8 @@ type EntityRecognizedDocument struct {
 type KeyPhrasesExtractedDocument struct {
 	// ID Unique, non-empty document identifier.
 	ID string `json:"id"`
+	// Statistics (Optional) If showStats=true was specified in the request this field will contain information about the document payload.
+	Statistics *DocumentStatistics `json:"statistics,omitempty"`
 	// Warnings Warnings encountered while processing document.
 	Warnings   []DocumentWarning `json:"warnings"`
 	KeyPhrases []string          `json:"keyPhrases"`
@@ -188,12 +340,58 @@ type SentimentConfidenceScores struct {
 	Neutral  float64 `json:"neutral"`
 }
 
+type TargetConfidenceScores struct {
+	Positive float64 `json:"positive"`
+	Negative float64 `json:"negative"`
+}
+
+type TargetRelation struct {
+	// Ref The JSON pointer indicating the linked object.
+	Ref string `json:"ref"`
+	// RelationType The type related to the target ("assessment" or "target").
+	RelationType string `json:"relationType"`
+}
+
+type SentenceTarget struct {
+	// ConfidenceScores Target level sentiment confidence scores for the target in the sentence.
+	ConfidenceScores TargetConfidenceScores `json:"confidenceScores"`
+	// Length The length of the target.
+	Length int `json:"length"`
+	// Offset The target offset from the start of the sentence.
+	Offset int `json:"offset"`
+	// Relations The array of either assessment or target objects which is related to the target.
+	Relations []TargetRelation `json:"relations"`
+	// Sentiment Targeted sentiment in the sentence ("positive", "mixed" or "negative").
+	Sentiment Sentiment `json:"sentiment"`
+	// Text The target text detected.
+	Text string `json:"text"`
+}
+
+type SentenceAssessment struct {
+	// ConfidenceScores Assessment level sentiment confidence scores for the assessment in the sentence.
+	ConfidenceScores TargetConfidenceScores `json:"confidenceScores"`
+	// IsNegated The indicator representing if the assessment is negated.
+	IsNegated bool `json:"isNegated"`
+	// Length The length of the assessment.
+	Length int `json:"length"`
+	// Offset The assessment offset from the start of the sentence.
+	Offset int `json:"offset"`
+	// Sentiment Assessment sentiment in the sentence ("positive", "mixed" or "negative").
+	Sentiment Sentiment `json:"sentiment"`
+	// Text The assessment text detected.
+	Text string `json:"text"`
+}
+
 type SentenceSentiment struct {
 	Sentiment        Sentiment                 `json:"sentiment"`
 	ConfidenceScores SentimentConfidenceScores `json:"confidenceScores"`
 	Offset           int                       `json:"offset"`
 	Length           int                       `json:"length"`
 	Text             string                    `json:"text"`
+	// Targets (Optional) The array of sentence targets for the sentence. Only returned when OpinionMining is enabled.
+	Targets []SentenceTarget `json:"targets,omitempty"`
+	// Assessments (Optional) The array of assessments for the sentence. Only returned when OpinionMining is enabled.
+	Assessments []SentenceAssessment `json:"assessments,omitempty"`
 }
 
 type SentimentAnalyzedDocument struct {
@@ -202,7 +400,9 @@ type SentimentAnalyzedDocument struct {
 	Sentiment        Sentiment                 `json:"sentiment"`
 	ConfidenceScores SentimentConfidenceScores `json:"confidenceScores"`
 	Sentences        []SentenceSentiment       `json:"sentences"`
-	Warnings         []DocumentWarning         `json:"warnings"`
+	// Statistics (Optional) If showStats=true was specified in the request this field will contain information about the document payload.
+	Statistics *DocumentStatistics `json:"statistics,omitempty"`
+	Warnings   []DocumentWarning   `json:"warnings"`
 }
 
 type EntitiesResult struct {
@@ -212,6 +412,30 @@ type EntitiesResult struct {
 	Errors []DocumentError `json:"errors"`
 	// ModelVersion This field indicates which model is used for scoring.
 	ModelVersion string `json:"modelVersion"`
+	// Statistics (Optional) If showStats=true was specified in the request this field will contain information about the request payload.
+	Statistics *RequestStatistics `json:"statistics,omitempty"`
+}
+
+type EntityLinkingResult struct {
+	// Documents Response by document
+	Documents []LinkedEntitiesDocumentResult `json:"documents"`
+	// Errors Errors by document id.
+	Errors []DocumentError `json:"errors"`
+	// ModelVersion This field indicates which model is used for scoring.
+	ModelVersion string `json:"modelVersion"`
+	// Statistics (Optional) If showStats=true was specified in the request this field will contain information about the request payload.
+	Statistics *RequestStatistics `json:"statistics,omitempty"`
+}
+
+type PiiResult struct {
+	// Documents Response by document
+	Documents []PiiEntitiesDocumentResult `json:"documents"`
+	// Errors Errors by document id.
+	Errors []DocumentError `json:"errors"`
+	// ModelVersion This field indicates which model is used for scoring.
+	ModelVersion string `json:"modelVersion"`
+	// Statistics (Optional) If showStats=true was specified in the request this field will contain information about the request payload.
+	Statistics *RequestStatistics `json:"statistics,omitempty"`
 }
 
 type KeyPhraseResult struct {
@@ -221,6 +445,8 @@ type KeyPhraseResult struct {
 	Errors []DocumentError `json:"errors"`
 	// ModelVersion This field indicates which model is used for scoring.
 	ModelVersion string `json:"modelVersion"`
+	// Statistics (Optional) If showStats=true was specified in the request this field will contain information about the request payload.
+	Statistics *RequestStatistics `json:"statistics,omitempty"`
 }
 
 type SentimentResponse struct {
@@ -230,6 +456,8 @@ type SentimentResponse struct {
 	Errors []DocumentError `json:"errors"`
 	// ModelVersion This field indicates which model is used for scoring.
 	ModelVersion string `json:"modelVersion"`
+	// Statistics (Optional) If showStats=true was specified in the request this field will contain information about the request payload.
+	Statistics *RequestStatistics `json:"statistics,omitempty"`
 }
 
 type SummaryContext struct {
@@ -251,6 +479,8 @@ type AbstractiveSummaryDocumentResult struct {
 	ID string `json:"id"`
 	// Summaries A list of abstractive summaries.
 	Summaries []AbstractiveSummary `json:"summaries"`
+	// Statistics (Optional) If showStats=true was specified in the request this field will contain information about the document payload.
+	Statistics *DocumentStatistics `json:"statistics,omitempty"`
 	// Warnings Warnings encountered while processing document.
 	Warnings []DocumentWarning `json:"warnings"`
 }
@@ -262,6 +492,8 @@ type AbstractiveSummarizationResult struct {
 	Errors []DocumentError `json:"errors"`
 	// ModelVersion This field indicates which model is used for scoring.
 	ModelVersion string `json:"modelVersion"`
+	// Statistics (Optional) If showStats=true was specified in the request this field will contain information about the request payload.
+	Statistics *RequestStatistics `json:"statistics,omitempty"`
 }
 
 type ExtractedSummarySentence struct {
@@ -280,6 +512,8 @@ type ExtractedSummaryDocumentResult struct {
 	ID string `json:"id"`
 	// Sentences A ranked list of sentences representing the extracted summary.
 	Sentences []ExtractedSummarySentence `json:"sentences"`
+	// Statistics (Optional) If showStats=true was specified in the request this field will contain information about the document payload.
+	Statistics *DocumentStatistics `json:"statistics,omitempty"`
 	// Warnings Warnings encountered while processing document.
 	Warnings []DocumentWarning `json:"warnings"`
 }
@@ -291,4 +525,168 @@ type ExtractiveSummarizationResult struct {
 	Errors []DocumentError `json:"errors"`
 	// ModelVersion This field indicates which model is used for scoring.
 	ModelVersion string `json:"modelVersion"`
+	// Statistics (Optional) If showStats=true was specified in the request this field will contain information about the request payload.
+	Statistics *RequestStatistics `json:"statistics,omitempty"`
+}
+
+type CustomEntitiesResult struct {
+	// DeploymentName This field indicates the deployment name for the model.
+	DeploymentName string `json:"deploymentName"`
+	// Documents Response by document
+	Documents []EntityRecognizedDocument `json:"documents"`
+	// Errors Errors by document id.
+	Errors []DocumentError `json:"errors"`
+	// ProjectName This field indicates the project name for the model.
+	ProjectName string `json:"projectName"`
+	// Statistics (Optional) If showStats=true was specified in the request this field will contain information about the request payload.
+	Statistics *RequestStatistics `json:"statistics,omitempty"`
+}
+
+type ClassificationResult struct {
+	// Category Classification type.
+	Category string `json:"category"`
+	// ConfidenceScore Confidence score between 0 and 1 of the recognized class.
+	ConfidenceScore float64 `json:"confidenceScore"`
+}
+
+type ClassificationDocumentResult struct {
+	// Class Recognized classes. Single label classification returns at most one class.
+	Class []ClassificationResult `json:"class"`
+	// ID Unique, non-empty document identifier.
+	ID string `json:"id"`
+	// Statistics (Optional) If showStats=true was specified in the request this field will contain information about the document payload.
+	Statistics *DocumentStatistics `json:"statistics,omitempty"`
+	// Warnings Warnings encountered while processing document.
+	Warnings []DocumentWarning `json:"warnings"`
+}
+
+type CustomLabelClassificationResult struct {
+	// DeploymentName This field indicates the deployment name for the model.
+	DeploymentName string `json:"deploymentName"`
+	// Documents Response by document
+	Documents []ClassificationDocumentResult `json:"documents"`
+	// Errors Errors by document id.
+	Errors []DocumentError `json:"errors"`
+	// ProjectName This field indicates the project name for the model.
+	ProjectName string `json:"projectName"`
+	// Statistics (Optional) If showStats=true was specified in the request this field will contain information about the request payload.
+	Statistics *RequestStatistics `json:"statistics,omitempty"`
+}
+
+type HealthcareAssertion struct {
+	// Association Describes if the entity is the subject of the text or if it describes someone else ("subject" or "other").
+	Association string `json:"association,omitempty"`
+	// Certainty Describes the entities certainty and polarity ("positive", "positivePossible", "neutralPossible", "negativePossible" or "negative").
+	Certainty string `json:"certainty,omitempty"`
+	// Conditionality Describes any conditionality on the entity ("hypothetical" or "conditional").
+	Conditionality string `json:"conditionality,omitempty"`
+	// Temporality Describes temporal information regarding the entity ("current", "past" or "future").
+	Temporality string `json:"temporality,omitempty"`
+}
+
+type HealthcareEntityLink struct {
+	// DataSource Entity Catalog. Examples include: UMLS, CHV, MSH, etc.
+	DataSource string `json:"dataSource"`
+	// ID Entity id in the given source catalog.
+	ID string `json:"id"`
+}
+
+type HealthcareEntity struct {
+	// Assertion (Optional) Assertion of the entity.
+	Assertion *HealthcareAssertion `json:"assertion,omitempty"`
+	// Category Healthcare Entity Category.
+	Category string `json:"category"`
+	// ConfidenceScore Confidence score between 0 and 1 of the extracted entity.
+	ConfidenceScore float64 `json:"confidenceScore"`
+	// Length Length for the entity text. Use of different 'stringIndexType' values can affect the length returned.
+	Length int `json:"length"`
+	// Links Entity references in known data sources.
+	Links []HealthcareEntityLink `json:"links"`
+	// Name Preferred name for the entity. Example: 'histologically' would have a 'name' of 'histologic'.
+	Name string `json:"name"`
+	// Offset Start position for the entity text. Use of different 'stringIndexType' values can affect the offset returned.
+	Offset int `json:"offset"`
+	// SubCategory (Optional) Entity sub type.
+	SubCategory string `json:"subcategory"`
+	// Text Entity text as appears in the request.
+	Text string `json:"text"`
+}
+
+type HealthcareRelationEntity struct {
+	// Ref Reference link object, using a JSON pointer RFC 6901 (URI Fragment Identifier Representation), pointing to the entity.
+	Ref string `json:"ref"`
+	// Role Role of entity in the relationship. For example: 'CD20-positive diffuse large B-cell lymphoma' has the following entities with their roles in parenthesis: CD20 (GeneOrProtein), Positive (Expression), diffuse large B-cell lymphoma (Diagnosis).
+	Role string `json:"role"`
+}
+
+type HealthcareRelation struct {
+	// ConfidenceScore Confidence score between 0 and 1 of the extracted relation.
+	ConfidenceScore float64 `json:"confidenceScore"`
+	// Entities The entities in the relation.
+	Entities []HealthcareRelationEntity `json:"entities"`
+	// RelationType Type of relation. Examples include: DosageOfMedication or 'FrequencyOfMedication', etc.
+	RelationType string `json:"relationType"`
+}
+
+type HealthcareEntitiesDocumentResult struct {
+	// Entities Healthcare entities.
+	Entities []HealthcareEntity `json:"entities"`
+	// FhirBundle (Optional) JSON bundle containing a FHIR compatible object for consumption in other Healthcare tools. Only returned when FhirVersion is set. For additional information see https://www.hl7.org/fhir/overview.html.
+	FhirBundle json.RawMessage `json:"fhirBundle,omitempty"`
+	// ID Unique, non-empty document identifier.
+	ID string `json:"id"`
+	// Relations Healthcare entity relations.
+	Relations []HealthcareRelation `json:"relations"`
+	// Statistics (Optional) If showStats=true was specified in the request this field will contain information about the document payload.
+	Statistics *DocumentStatistics `json:"statistics,omitempty"`
+	// Warnings Warnings encountered while processing document.
+	Warnings []DocumentWarning `json:"warnings"`
+}
+
+// DecodeFhirBundle decodes FhirBundle into a FhirBundle. It returns nil without error if the bundle was not requested.
+func (d HealthcareEntitiesDocumentResult) DecodeFhirBundle() (*FhirBundle, error) {
+	if len(d.FhirBundle) == 0 || string(d.FhirBundle) == "null" {
+		return nil, nil
+	}
+	var bundle FhirBundle
+	if err := json.Unmarshal(d.FhirBundle, &bundle); err != nil {
+		return nil, err
+	}
+	return &bundle, nil
+}
+
+type HealthcareResult struct {
+	// Documents Response by document
+	Documents []HealthcareEntitiesDocumentResult `json:"documents"`
+	// Errors Errors by document id.
+	Errors []DocumentError `json:"errors"`
+	// ModelVersion This field indicates which model is used for scoring.
+	ModelVersion string `json:"modelVersion"`
+	// Statistics (Optional) If showStats=true was specified in the request this field will contain information about the request payload.
+	Statistics *RequestStatistics `json:"statistics,omitempty"`
+}
+
+// FhirBundle is the envelope of a FHIR R4 Bundle. Resources are kept as raw JSON so they can be decoded with any FHIR model.
+type FhirBundle struct {
+	ResourceType string            `json:"resourceType"`
+	ID           string            `json:"id"`
+	Type         string            `json:"type"`
+	Timestamp    string            `json:"timestamp,omitempty"`
+	Entry        []FhirBundleEntry `json:"entry"`
+}
+
+type FhirBundleEntry struct {
+	FullURL  string          `json:"fullUrl"`
+	Resource json.RawMessage `json:"resource"`
+}
+
+// ResourceType returns the resourceType of the entry resource (e.g. "Composition", "Patient", "Condition").
+func (e FhirBundleEntry) ResourceType() (string, error) {
+	var header struct {
+		ResourceType string `json:"resourceType"`
+	}
+	if err := json.Unmarshal(e.Resource, &header); err != nil {
+		return "", err
+	}
+	return header.ResourceType, nil
 }
@@ -0,0 +1,96 @@
+package v20230401
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MinedOpinion is a target of a sentence together with the assessments the service related to it.
+type MinedOpinion struct {
+	Sentence    *SentenceSentiment
+	Target      *SentenceTarget
+	Assessments []*SentenceAssessment
+}
+
+// MinedOpinions resolves the relations of every sentence target into direct pointers into the document.
+// The returned pointers share memory with d.
+func (d *SentimentAnalyzedDocument) MinedOpinions() ([]MinedOpinion, error) {
+	var opinions []MinedOpinion
+	for i := range d.Sentences {
+		sentence := &d.Sentences[i]
+		for j := range sentence.Targets {
+			target := &sentence.Targets[j]
+			opinion := MinedOpinion{Sentence: sentence, Target: target}
+			for _, relation := range target.Relations {
+				if relation.RelationType != "assessment" {
+					continue
+				}
+				assessment, err := d.ResolveAssessment(relation.Ref)
+				if err != nil {
+					return nil, err
+				}
+				opinion.Assessments = append(opinion.Assessments, assessment)
+			}
+			opinions = append(opinions, opinion)
+		}
+	}
+	return opinions, nil
+}
+
+// ResolveAssessment resolves a JSON pointer such as "#/documents/0/sentences/1/assessments/0" against the document.
+// The document index of the pointer is ignored because relations never cross document boundaries.
+func (d *SentimentAnalyzedDocument) ResolveAssessment(ref string) (*SentenceAssessment, error) {
+	sentence, index, err := d.resolveSentenceRef(ref, "assessments")
+	if err != nil {
+		return nil, err
+	}
+	if index >= len(sentence.Assessments) {
+		return nil, fmt.Errorf("assessment reference out of range: %s", ref)
+	}
+	return &sentence.Assessments[index], nil
+}
+
+// ResolveTarget resolves a JSON pointer such as "#/documents/0/sentences/1/targets/0" against the document.
+// The document index of the pointer is ignored because relations never cross document boundaries.
+func (d *SentimentAnalyzedDocument) ResolveTarget(ref string) (*SentenceTarget, error) {
+	sentence, index, err := d.resolveSentenceRef(ref, "targets")
+	if err != nil {
+		return nil, err
+	}
+	if index >= len(sentence.Targets) {
+		return nil, fmt.Errorf("target reference out of range: %s", ref)
+	}
+	return &sentence.Targets[index], nil
+}
+
+func (d *SentimentAnalyzedDocument) resolveSentenceRef(ref string, collection string) (*SentenceSentiment, int, error) {
+	sentenceIndex, kind, index, err := parseSentenceRef(ref)
+	if err != nil {
+		return nil, 0, err
+	}
+	if kind != collection {
+		return nil, 0, fmt.Errorf("reference does not point to %s: %s", collection, ref)
+	}
+	if sentenceIndex >= len(d.Sentences) {
+		return nil, 0, fmt.Errorf("sentence reference out of range: %s", ref)
+	}
+	return &d.Sentences[sentenceIndex], index, nil
+}
+
+// parseSentenceRef extracts the sentence index, the collection name and the item index from a JSON pointer
+// ending in "/sentences/{sentence}/{collection}/{index}".
+func parseSentenceRef(ref string) (sentenceIndex int, collection string, index int, err error) {
+	segments := strings.Split(strings.TrimPrefix(ref, "#"), "/")
+	n := len(segments)
+	if n < 4 || segments[n-4] != "sentences" {
+		return 0, "", 0, fmt.Errorf("invalid sentence reference: %s", ref)
+	}
+	if sentenceIndex, err = strconv.Atoi(segments[n-3]); err != nil || sentenceIndex < 0 {
+		return 0, "", 0, fmt.Errorf("invalid sentence reference: %s", ref)
+	}
+	if index, err = strconv.Atoi(segments[n-1]); err != nil || index < 0 {
+		return 0, "", 0, fmt.Errorf("invalid sentence reference: %s", ref)
+	}
+	return sentenceIndex, segments[n-2], index, nil
+}
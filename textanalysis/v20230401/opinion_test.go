@@ -0,0 +1,90 @@
+package v20230401_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/kde713/azurelangai-go/textanalysis/v20230401"
+)
+
+const opinionMiningResponse = `{
+	"documents": [{
+		"id": "1",
+		"sentiment": "mixed",
+		"confidenceScores": {"positive": 0.5, "neutral": 0.0, "negative": 0.5},
+		"sentences": [
+			{
+				"sentiment": "positive", "confidenceScores": {"positive": 1.0, "neutral": 0.0, "negative": 0.0},
+				"offset": 0, "length": 28, "text": "The food was delicious.",
+				"targets": [{"sentiment": "positive", "confidenceScores": {"positive": 1.0, "negative": 0.0}, "offset": 4, "length": 4, "text": "food",
+					"relations": [{"relationType": "assessment", "ref": "#/documents/0/sentences/0/assessments/0"}]}],
+				"assessments": [{"sentiment": "positive", "confidenceScores": {"positive": 1.0, "negative": 0.0}, "offset": 13, "length": 9, "text": "delicious", "isNegated": false}]
+			},
+			{
+				"sentiment": "negative", "confidenceScores": {"positive": 0.0, "neutral": 0.0, "negative": 1.0},
+				"offset": 29, "length": 37, "text": "The staff was not friendly or quick.",
+				"targets": [{"sentiment": "negative", "confidenceScores": {"positive": 0.0, "negative": 1.0}, "offset": 4, "length": 5, "text": "staff",
+					"relations": [
+						{"relationType": "assessment", "ref": "#/documents/0/sentences/1/assessments/0"},
+						{"relationType": "assessment", "ref": "#/documents/0/sentences/1/assessments/1"}
+					]}],
+				"assessments": [
+					{"sentiment": "negative", "confidenceScores": {"positive": 0.0, "negative": 1.0}, "offset": 18, "length": 8, "text": "friendly", "isNegated": true},
+					{"sentiment": "negative", "confidenceScores": {"positive": 0.0, "negative": 1.0}, "offset": 30, "length": 5, "text": "quick", "isNegated": true}
+				]
+			}
+		],
+		"warnings": []
+	}],
+	"errors": [],
+	"modelVersion": "2022-11-01"
+}`
+
+func TestSentimentAnalyzedDocument_MinedOpinions(t *testing.T) {
+	var result v20230401.SentimentResponse
+	if err := json.Unmarshal([]byte(opinionMiningResponse), &result); err != nil {
+		t.Fatal(err)
+	}
+	doc := &result.Documents[0]
+	opinions, err := doc.MinedOpinions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(opinions) != 2 {
+		t.Fatalf("Expected 2 opinions, got %d", len(opinions))
+	}
+	if opinions[0].Target.Text != "food" || len(opinions[0].Assessments) != 1 || opinions[0].Assessments[0].Text != "delicious" {
+		t.Errorf("Unexpected first opinion: %+v", opinions[0])
+	}
+	if opinions[1].Sentence != &doc.Sentences[1] {
+		t.Error("Expected opinion to point into the document sentences")
+	}
+	if len(opinions[1].Assessments) != 2 || !opinions[1].Assessments[0].IsNegated || opinions[1].Assessments[1].Text != "quick" {
+		t.Errorf("Unexpected second opinion assessments: %+v", opinions[1].Assessments)
+	}
+}
+
+func TestSentimentAnalyzedDocument_ResolveTarget(t *testing.T) {
+	var result v20230401.SentimentResponse
+	if err := json.Unmarshal([]byte(opinionMiningResponse), &result); err != nil {
+		t.Fatal(err)
+	}
+	doc := &result.Documents[0]
+	target, err := doc.ResolveTarget("#/documents/0/sentences/1/targets/0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if target.Text != "staff" {
+		t.Errorf("Expected target staff, got %s", target.Text)
+	}
+	for _, ref := range []string{
+		"#/documents/0/sentences/2/targets/0",
+		"#/documents/0/sentences/1/assessments/0",
+		"#/documents/0/sentences/x/targets/0",
+		"not-a-pointer",
+	} {
+		if _, err := doc.ResolveTarget(ref); err == nil {
+			t.Errorf("Expected error for %s", ref)
+		}
+	}
+}
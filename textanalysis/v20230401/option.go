@@ -1,22 +1,198 @@
 package v20230401
 
 import (
+	"net/http"
 	"time"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type options struct {
+	// HTTP
+	httpClient *http.Client
+	transport  http.RoundTripper
+	timeout    time.Duration
+	userAgent  string
+	headers    map[string]string
+
 	// Retry
-	retryCount       int
-	retryWaitTime    time.Duration
-	retryMaxWaitTime time.Duration
+	retry       RetryPolicy
+	unsafeRetry *RetryPolicy
+
+	// Rate limit
+	limiter  *RateLimiter
+	adaptive *AdaptiveLimiter
+
+	// Job submission
+	jobDedup JobDedupStore
+
+	// Tracing
+	tracerProvider trace.TracerProvider
+	propagator     propagation.TextMapPropagator
+
+	// Metrics
+	metrics MetricsSink
+
+	// Logging
+	logger *requestLogger
+
+	// Statistics
+	showStats bool
+
+	// Document errors
+	strict bool
 }
 
 type Option func(*options)
 
+// WithHTTPClient sets the HTTP client used to send requests, e.g. with a custom TLS configuration or proxy.
+// The client is copied, so later changes to it have no effect.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(o *options) {
+		o.httpClient = httpClient
+	}
+}
+
+// WithTransport sets the round tripper used to send requests. It replaces the transport of the client given to
+// WithHTTPClient, if any.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(o *options) {
+		o.transport = transport
+	}
+}
+
+// WithTimeout limits the duration of every attempt of a request, including reading the response. Attempts that
+// time out are retried according to the retry policy. Zero means no limit.
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.timeout = timeout
+	}
+}
+
+// WithUserAgent appends suffix to the User-Agent header of every request, e.g. "myapp/1.2".
+func WithUserAgent(suffix string) Option {
+	return func(o *options) {
+		o.userAgent = suffix
+	}
+}
+
+// WithHeader sets a static header on every request. It can be given multiple times.
+func WithHeader(name string, value string) Option {
+	return func(o *options) {
+		if o.headers == nil {
+			o.headers = make(map[string]string)
+		}
+		o.headers[name] = value
+	}
+}
+
+// WithRetryCount retries failed requests up to count times with a jittered exponential backoff that starts at
+// minWait and is capped at maxWait. Requests whose Retry-After exceeds maxWait are not retried. It is a shorthand
+// for WithRetryPolicy.
 func WithRetryCount(count int, minWait time.Duration, maxWait time.Duration) Option {
 	return func(o *options) {
-		o.retryCount = count
-		o.retryWaitTime = minWait
-		o.retryMaxWaitTime = maxWait
+		o.retry = RetryPolicy{MaxRetries: count, BaseDelay: minWait, MaxDelay: maxWait}
+	}
+}
+
+// WithRetryPolicy sets the retry policy of the client. Retries are disabled by default.
+// Unless WithUnsafeRetryPolicy is given, the policy also applies to job submissions, restricted to throttled
+// (429) responses.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(o *options) {
+		o.retry = policy
+	}
+}
+
+// WithUnsafeRetryPolicy sets the retry policy of job submissions, which are not idempotent. They are retried on
+// throttled (429) responses only, whatever the policy.
+func WithUnsafeRetryPolicy(policy RetryPolicy) Option {
+	return func(o *options) {
+		o.unsafeRetry = &policy
+	}
+}
+
+// WithRateLimiter paces all requests of the client, including retries, with limiter. Pass the same limiter to
+// every client of a resource to share its quota.
+func WithRateLimiter(limiter *RateLimiter) Option {
+	return func(o *options) {
+		o.limiter = limiter
+	}
+}
+
+// WithRateLimit paces all requests of the client within limit, e.g. RateLimitS.
+func WithRateLimit(limit RateLimit) Option {
+	return WithRateLimiter(NewRateLimiter(limit))
+}
+
+// WithAdaptiveLimiter bounds the number of concurrent requests of the client, on every endpoint, with limiter.
+// The limit grows while requests succeed and is cut on 429 Too Many Requests and 503 Service Unavailable
+// responses. Use limiter.Stats to monitor it.
+func WithAdaptiveLimiter(limiter *AdaptiveLimiter) Option {
+	return func(o *options) {
+		o.adaptive = limiter
+	}
+}
+
+// WithJobDedup records submitted jobs in store, so that submitting the same SubmitJobRequestBody again returns
+// the existing job ID instead of creating a duplicate job. Concurrent submissions of the same request share a
+// single service call. A pending record is stored before the request is sent. If the submission ends in doubt, the
+// next submission doesn't send the request again: it returns the job ID from the Operation-Location header of the
+// failed response if there was one, and otherwise a *JobSubmissionInDoubtError wrapping ErrJobSubmissionPending,
+// as the service has no API to look the job up.
+func WithJobDedup(store JobDedupStore) Option {
+	return func(o *options) {
+		o.jobDedup = store
+	}
+}
+
+// WithTracerProvider creates an OpenTelemetry client span for every Client call with tracerProvider, e.g.
+// otel.GetTracerProvider(). Spans carry the task kind, API version, document count, HTTP status, apim-request-id,
+// retry count and document error count, and their trace context is propagated to the service.
+func WithTracerProvider(tracerProvider trace.TracerProvider) Option {
+	return func(o *options) {
+		o.tracerProvider = tracerProvider
+	}
+}
+
+// WithTextMapPropagator sets how the trace context is propagated on outgoing requests when tracing is enabled.
+// Defaults to W3C Trace Context.
+func WithTextMapPropagator(propagator propagation.TextMapPropagator) Option {
+	return func(o *options) {
+		o.propagator = propagator
+	}
+}
+
+// WithMetrics reports the metrics of every Client call to sink, e.g. an ExpvarMetrics.
+func WithMetrics(sink MetricsSink) Option {
+	return func(o *options) {
+		o.metrics = sink
+	}
+}
+
+// WithLogger logs every attempt of every request to logger with its method, path, API version, status, duration,
+// apim-request-id and attempt number. Bodies are only captured with WithLogBodies, and document texts in them are
+// redacted unless WithLogUnredactedText is given.
+func WithLogger(logger Logger, optAppliers ...LogOption) Option {
+	return func(o *options) {
+		o.logger = newRequestLogger(logger, optAppliers)
+	}
+}
+
+// WithShowStats requests request-level and document-level statistics on analyze-text calls and job status calls.
+func WithShowStats() Option {
+	return func(o *options) {
+		o.showStats = true
+	}
+}
+
+// WithStrictDocumentErrors makes calls whose result has document errors return a *DocumentErrors error together
+// with the partial result. Job status calls check the results once the job reached a terminal status. Clients created
+// with NewBatchingClient return the merged result of all batches with their document errors joined, and clients
+// created with NewChunkingClient return the stitched result with the errors of the original documents.
+func WithStrictDocumentErrors() Option {
+	return func(o *options) {
+		o.strict = true
 	}
 }
@@ -0,0 +1,128 @@
+package v20230401_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kde713/azurelangai-go/textanalysis/v20230401"
+)
+
+func TestWithShowStats(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("showStats"); got != "true" {
+			t.Errorf("Expected showStats=true, got %q", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodPost:
+			_, _ = w.Write([]byte(`{"kind":"KeyPhraseExtractionResults","results":{
+				"documents":[{"id":"1","keyPhrases":["billing"],"statistics":{"charactersCount":1200,"transactionsCount":2},"warnings":[]}],
+				"errors":[],"modelVersion":"2022-10-01",
+				"statistics":{"documentsCount":2,"validDocumentsCount":1,"erroneousDocumentsCount":1,"transactionsCount":2}}}`))
+		case http.MethodGet:
+			_, _ = w.Write([]byte(`{"jobId":"job-1","status":"succeeded","tasks":{"items":[]},
+				"statistics":{"documentsCount":3,"validDocumentsCount":3,"erroneousDocumentsCount":0,"transactionsCount":3}}`))
+		}
+	}))
+	defer srv.Close()
+	client := v20230401.NewClient(srv.URL, "key", v20230401.WithShowStats())
+
+	result, err := client.AnalyzeTextKeyPhraseExtraction(context.TODO(), v20230401.MultiLanguageAnalysisInput{
+		Documents: []v20230401.MultiLanguageInput{{ID: "1", Text: "..."}, {ID: "2", Text: ""}},
+	}, v20230401.KeyPhraseTaskParameters{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Statistics == nil || result.Statistics.ErroneousDocumentsCount != 1 || result.Statistics.TransactionsCount != 2 {
+		t.Errorf("Unexpected request statistics: %+v", result.Statistics)
+	}
+	if stats := result.Documents[0].Statistics; stats == nil || stats.CharactersCount != 1200 {
+		t.Errorf("Unexpected document statistics: %+v", stats)
+	}
+
+	jobResp, err := client.GetTextAnalyticsJobResult(context.TODO(), "job-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if jobResp.Statistics == nil || jobResp.Statistics.DocumentsCount != 3 {
+		t.Errorf("Unexpected job statistics: %+v", jobResp.Statistics)
+	}
+}
+
+type recordingTransport struct {
+	requests int32
+	next     http.RoundTripper
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	atomic.AddInt32(&t.requests, 1)
+	return t.next.RoundTrip(req)
+}
+
+func TestWithHTTPClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("User-Agent"); !strings.HasPrefix(got, "azurelangai-go") || !strings.HasSuffix(got, " myapp/1.2") {
+			t.Errorf("Expected User-Agent with suffix myapp/1.2, got %q", got)
+		}
+		if got := r.Header.Get("X-Tenant"); got != "contoso" {
+			t.Errorf("Expected X-Tenant contoso, got %q", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jobId":"job-1","status":"running","tasks":{"items":[]}}`))
+	}))
+	defer srv.Close()
+	transport := &recordingTransport{next: srv.Client().Transport}
+	httpClient := &http.Client{}
+	client := v20230401.NewClient(srv.URL, "key",
+		v20230401.WithHTTPClient(httpClient),
+		v20230401.WithTransport(transport),
+		v20230401.WithUserAgent("myapp/1.2"),
+		v20230401.WithHeader("X-Tenant", "contoso"),
+	)
+
+	if _, err := client.GetTextAnalyticsJobResult(context.TODO(), "job-1"); err != nil {
+		t.Fatal(err)
+	}
+	if transport.requests != 1 {
+		t.Errorf("Expected 1 request through the transport, got %d", transport.requests)
+	}
+	if httpClient.Transport != nil || httpClient.Jar != nil {
+		t.Error("Expected the given HTTP client to be left unchanged")
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			select {
+			case <-time.After(time.Second):
+			case <-r.Context().Done():
+			}
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jobId":"job-1","status":"running","tasks":{"items":[]}}`))
+	}))
+	defer srv.Close()
+	client := v20230401.NewClient(srv.URL, "key",
+		v20230401.WithTimeout(50*time.Millisecond),
+		v20230401.WithRetryCount(1, time.Millisecond, time.Millisecond),
+	)
+
+	start := time.Now()
+	if _, err := client.GetTextAnalyticsJobResult(context.TODO(), "job-1"); err != nil {
+		t.Fatal(err)
+	}
+	if got := atomic.LoadInt32(&requests); got != 2 {
+		t.Errorf("Expected the timed out attempt to be retried, got %d requests", got)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected the first attempt to time out, took %s", elapsed)
+	}
+}
@@ -0,0 +1,148 @@
+package v20230401
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrJobWaitTimeout is returned by JobPoller when the job does not reach a terminal status within PollingPolicy.MaxWait.
+var ErrJobWaitTimeout = errors.New("job did not finish within the maximum wait time")
+
+// PollingPolicy controls how often JobPoller queries the job status.
+type PollingPolicy struct {
+	// Interval Delay between status checks. The first check is sent immediately. Defaults to 1 second.
+	Interval time.Duration
+	// Multiplier Factor applied to the interval after each check that did not finish the job. Values below 1 keep the interval fixed.
+	Multiplier float64
+	// MaxInterval (Optional) Upper bound of the interval once the multiplier has been applied.
+	MaxInterval time.Duration
+	// MaxWait (Optional) Maximum total time spent waiting for the job. Zero means no limit other than the context.
+	MaxWait time.Duration
+}
+
+// DefaultPollingPolicy polls every second, backing off up to 10 seconds between checks.
+var DefaultPollingPolicy = PollingPolicy{
+	Interval:    time.Second,
+	Multiplier:  1.5,
+	MaxInterval: 10 * time.Second,
+}
+
+func (p PollingPolicy) nextInterval(current time.Duration) time.Duration {
+	if p.Multiplier <= 1 {
+		return current
+	}
+	next := time.Duration(float64(current) * p.Multiplier)
+	if p.MaxInterval > 0 && next > p.MaxInterval {
+		next = p.MaxInterval
+	}
+	return next
+}
+
+// JobProgress is a snapshot of the job status passed to progress callbacks.
+type JobProgress struct {
+	JobID      string
+	Status     JobStatus
+	Completed  int
+	InProgress int
+	Failed     int
+	Total      int
+}
+
+// JobProgressFunc is called after every status check.
+type JobProgressFunc func(progress JobProgress)
+
+// JobPoller waits for jobs submitted with Client.SubmitTextAnalyticsJob to finish.
+type JobPoller struct {
+	client     Client
+	policy     PollingPolicy
+	onProgress JobProgressFunc
+}
+
+// NewJobPoller creates a JobPoller. A zero Interval in policy is replaced with DefaultPollingPolicy.Interval.
+func NewJobPoller(c Client, policy PollingPolicy) *JobPoller {
+	if policy.Interval <= 0 {
+		policy.Interval = DefaultPollingPolicy.Interval
+	}
+	return &JobPoller{
+		client: c,
+		policy: policy,
+	}
+}
+
+// OnProgress registers a callback invoked with the task counters after every status check.
+func (p *JobPoller) OnProgress(fn JobProgressFunc) *JobPoller {
+	p.onProgress = fn
+	return p
+}
+
+// Wait polls the job until it reaches a terminal status and returns the last status response.
+// Polling stops when ctx is done or PollingPolicy.MaxWait elapses, including during a status check; in the latter
+// case ErrJobWaitTimeout is returned together with the last status response received.
+func (p *JobPoller) Wait(ctx context.Context, jobID string) (*JobStatusResponse, error) {
+	parent := ctx
+	if p.policy.MaxWait > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.policy.MaxWait)
+		defer cancel()
+	}
+
+	var last *JobStatusResponse
+	interval := p.policy.Interval
+	for {
+		jobResp, err := p.client.GetTextAnalyticsJobResult(ctx, jobID)
+		if err != nil {
+			// Strict clients return the final status response together with *DocumentErrors.
+			if jobResp != nil {
+				return jobResp, err
+			}
+			if parent.Err() == nil && ctx.Err() != nil {
+				return last, waitTimeoutError(jobID, last)
+			}
+			return last, err
+		}
+		last = jobResp
+		if p.onProgress != nil {
+			p.onProgress(JobProgress{
+				JobID:      jobID,
+				Status:     jobResp.Status,
+				Completed:  jobResp.Tasks.Completed,
+				InProgress: jobResp.Tasks.InProgress,
+				Failed:     jobResp.Tasks.Failed,
+				Total:      jobResp.Tasks.Total,
+			})
+		}
+		if jobResp.Status.IsTerminal() {
+			return jobResp, nil
+		}
+
+		wait := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			wait.Stop()
+			if err := parent.Err(); err != nil {
+				return last, err
+			}
+			return last, waitTimeoutError(jobID, last)
+		case <-wait.C:
+		}
+		interval = p.policy.nextInterval(interval)
+	}
+}
+
+func waitTimeoutError(jobID string, last *JobStatusResponse) error {
+	if last == nil {
+		return fmt.Errorf("job %s: %w", jobID, ErrJobWaitTimeout)
+	}
+	return fmt.Errorf("job %s is %s: %w", jobID, last.Status, ErrJobWaitTimeout)
+}
+
+// CancelAndWait requests cancellation of the job and waits until it reaches a terminal status.
+// A job that finished before the cancellation took effect keeps its original terminal status.
+func (p *JobPoller) CancelAndWait(ctx context.Context, jobID string) (*JobStatusResponse, error) {
+	if _, err := p.client.CancelTextAnalyticsJob(ctx, jobID); err != nil {
+		return nil, err
+	}
+	return p.Wait(ctx, jobID)
+}
@@ -0,0 +1,146 @@
+package v20230401_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kde713/azurelangai-go/textanalysis/v20230401"
+)
+
+func newJobStatusServer(t *testing.T, statuses ...v20230401.JobStatus) (*httptest.Server, *int32) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(atomic.AddInt32(&calls, 1)) - 1
+		if n >= len(statuses) {
+			n = len(statuses) - 1
+		}
+		completed := 0
+		if statuses[n].IsTerminal() {
+			completed = 1
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"jobId":"job-1","status":%q,"tasks":{"completed":%d,"failed":0,"inProgress":%d,"total":1,"items":[]}}`,
+			statuses[n], completed, 1-completed)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func TestJobPoller_Wait(t *testing.T) {
+	srv, calls := newJobStatusServer(t, v20230401.StatusNotStarted, v20230401.StatusRunning, v20230401.StatusSucceeded)
+	client := v20230401.NewClient(srv.URL, "key")
+
+	var progress []v20230401.JobProgress
+	poller := v20230401.NewJobPoller(client, v20230401.PollingPolicy{Interval: time.Millisecond, Multiplier: 2, MaxInterval: 4 * time.Millisecond}).
+		OnProgress(func(p v20230401.JobProgress) {
+			progress = append(progress, p)
+		})
+	jobResp, err := poller.Wait(context.TODO(), "job-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if jobResp.Status != v20230401.StatusSucceeded {
+		t.Errorf("Expected status %s, got %s", v20230401.StatusSucceeded, jobResp.Status)
+	}
+	if got := atomic.LoadInt32(calls); got != 3 {
+		t.Errorf("Expected 3 status checks, got %d", got)
+	}
+	if len(progress) != 3 {
+		t.Fatalf("Expected 3 progress callbacks, got %d", len(progress))
+	}
+	if progress[1].InProgress != 1 || progress[2].Completed != 1 {
+		t.Errorf("Unexpected progress counters: %+v", progress)
+	}
+}
+
+func TestJobPoller_WaitTimeout(t *testing.T) {
+	srv, _ := newJobStatusServer(t, v20230401.StatusRunning)
+	client := v20230401.NewClient(srv.URL, "key")
+
+	poller := v20230401.NewJobPoller(client, v20230401.PollingPolicy{Interval: time.Millisecond, MaxWait: 20 * time.Millisecond})
+	jobResp, err := poller.Wait(context.TODO(), "job-1")
+	if !errors.Is(err, v20230401.ErrJobWaitTimeout) {
+		t.Fatalf("Expected ErrJobWaitTimeout, got %v", err)
+	}
+	if jobResp == nil || jobResp.Status != v20230401.StatusRunning {
+		t.Errorf("Expected last status response to be returned, got %+v", jobResp)
+	}
+}
+
+func TestJobPoller_WaitTimeoutDuringStatusCheck(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	client := v20230401.NewClient(srv.URL, "key")
+
+	start := time.Now()
+	poller := v20230401.NewJobPoller(client, v20230401.PollingPolicy{Interval: time.Millisecond, MaxWait: 20 * time.Millisecond})
+	if _, err := poller.Wait(context.TODO(), "job-1"); !errors.Is(err, v20230401.ErrJobWaitTimeout) {
+		t.Fatalf("Expected ErrJobWaitTimeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected the status check to be cut at MaxWait, took %s", elapsed)
+	}
+}
+
+func TestJobPoller_WaitContextCanceled(t *testing.T) {
+	srv, _ := newJobStatusServer(t, v20230401.StatusRunning)
+	client := v20230401.NewClient(srv.URL, "key")
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 20*time.Millisecond)
+	defer cancel()
+	poller := v20230401.NewJobPoller(client, v20230401.PollingPolicy{Interval: time.Millisecond})
+	if _, err := poller.Wait(ctx, "job-1"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestJobPoller_CancelAndWait(t *testing.T) {
+	var cancelled int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/language/analyze-text/jobs/job-1:cancel":
+			atomic.StoreInt32(&cancelled, 1)
+			w.Header().Set("Operation-Location", "http://"+r.Host+"/language/analyze-text/jobs/job-1?api-version="+v20230401.APIVersion)
+			w.WriteHeader(http.StatusAccepted)
+		case r.Method == http.MethodGet && r.URL.Path == "/language/analyze-text/jobs/job-1":
+			status := v20230401.StatusRunning
+			if atomic.LoadInt32(&cancelled) == 1 {
+				status = v20230401.StatusCancelled
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprintf(w, `{"jobId":"job-1","status":%q,"tasks":{"items":[]}}`, status)
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	client := v20230401.NewClient(srv.URL, "key")
+
+	jobLocation, err := client.CancelTextAnalyticsJob(context.TODO(), "job-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if jobID, _ := v20230401.ParseJobID(jobLocation); jobID != "job-1" {
+		t.Errorf("Expected Operation-Location of job-1, got %s", jobLocation)
+	}
+
+	jobResp, err := v20230401.NewJobPoller(client, v20230401.PollingPolicy{Interval: time.Millisecond}).CancelAndWait(context.TODO(), "job-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if jobResp.Status != v20230401.StatusCancelled {
+		t.Errorf("Expected status %s, got %s", v20230401.StatusCancelled, jobResp.Status)
+	}
+}
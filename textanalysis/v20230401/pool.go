@@ -0,0 +1,348 @@
+package v20230401
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// PoolStrategy selects the member of a PoolClient that serves the next call.
+type PoolStrategy int
+
+const (
+	// PoolRoundRobin rotates through the healthy members.
+	PoolRoundRobin PoolStrategy = iota
+	// PoolLeastOutstanding picks the healthy member with the fewest calls in progress.
+	PoolLeastOutstanding
+)
+
+// PoolMember is a Language resource of a PoolClient.
+type PoolMember struct {
+	// Name Identifies the member in errors and status, e.g. its region.
+	Name string
+	// Client Client of the resource, e.g. created with NewClient or NewClientWithTokenCredential.
+	Client Client
+}
+
+// PoolMemberStatus is a snapshot of a PoolClient member.
+type PoolMemberStatus struct {
+	// Name Name of the member.
+	Name string
+	// Healthy Whether the member is selected for new calls.
+	Healthy bool
+	// UnhealthyUntil End of the cooldown of an unhealthy member.
+	UnhealthyUntil time.Time
+	// Outstanding Number of calls in progress.
+	Outstanding int
+	// Failures Number of calls that failed over to another member.
+	Failures uint64
+}
+
+type poolOptions struct {
+	strategy     PoolStrategy
+	cooldown     time.Duration
+	jobRetention time.Duration
+}
+
+type PoolOption func(*poolOptions)
+
+// WithPoolStrategy sets how members are selected. Defaults to PoolRoundRobin.
+func WithPoolStrategy(strategy PoolStrategy) PoolOption {
+	return func(o *poolOptions) {
+		o.strategy = strategy
+	}
+}
+
+// WithPoolCooldown sets how long a failed member is skipped. Defaults to 30 seconds.
+func WithPoolCooldown(cooldown time.Duration) PoolOption {
+	return func(o *poolOptions) {
+		o.cooldown = cooldown
+	}
+}
+
+// WithPoolJobRetention sets how long the member of a submitted job is remembered. Defaults to 24 hours, which is
+// how long the service keeps job results.
+func WithPoolJobRetention(retention time.Duration) PoolOption {
+	return func(o *poolOptions) {
+		o.jobRetention = retention
+	}
+}
+
+type poolMember struct {
+	PoolMember
+	unhealthyUntil time.Time
+	outstanding    int
+	failures       uint64
+}
+
+type pinnedJob struct {
+	member      *poolMember
+	submittedAt time.Time
+}
+
+var _ Client = (*PoolClient)(nil)
+
+// PoolClient spreads calls across several Language resources, e.g. in different regions.
+//
+// Synchronous calls fail over to the next member on connection errors, 5xx responses and 429 Too Many Requests,
+// and the failed member is skipped for a cooldown. Job submissions fail over only when the job was certainly not
+// created; a *JobSubmissionInDoubtError is returned as is. Jobs stay pinned to the member that created them, so
+// their status and cancellation calls reach the right resource. Job IDs unknown to the pool, e.g. after a restart,
+// are looked up on every member.
+type PoolClient struct {
+	strategy     PoolStrategy
+	cooldown     time.Duration
+	jobRetention time.Duration
+
+	mu         sync.Mutex
+	members    []*poolMember
+	next       int
+	jobs       map[string]pinnedJob
+	lastPruned time.Time
+}
+
+// NewPoolClient creates a PoolClient over members.
+func NewPoolClient(members []PoolMember, optAppliers ...PoolOption) (*PoolClient, error) {
+	if len(members) == 0 {
+		return nil, errors.New("pool has no members")
+	}
+	o := poolOptions{
+		strategy:     PoolRoundRobin,
+		cooldown:     30 * time.Second,
+		jobRetention: 24 * time.Hour,
+	}
+	for _, applier := range optAppliers {
+		applier(&o)
+	}
+	p := &PoolClient{
+		strategy:     o.strategy,
+		cooldown:     o.cooldown,
+		jobRetention: o.jobRetention,
+		jobs:         make(map[string]pinnedJob),
+	}
+	for _, member := range members {
+		if member.Client == nil {
+			return nil, fmt.Errorf("pool member %q has no client", member.Name)
+		}
+		p.members = append(p.members, &poolMember{PoolMember: member})
+	}
+	return p, nil
+}
+
+// Status returns a snapshot of the members.
+func (p *PoolClient) Status() []PoolMemberStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now()
+	status := make([]PoolMemberStatus, len(p.members))
+	for i, m := range p.members {
+		status[i] = PoolMemberStatus{
+			Name:           m.Name,
+			Healthy:        !now.Before(m.unhealthyUntil),
+			UnhealthyUntil: m.unhealthyUntil,
+			Outstanding:    m.outstanding,
+			Failures:       m.failures,
+		}
+	}
+	return status
+}
+
+// acquire selects a member that is not in tried and counts the call as outstanding. When all untried members are
+// cooling down, the one whose cooldown ends first is selected.
+func (p *PoolClient) acquire(tried map[*poolMember]bool) *poolMember {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now()
+	selected, fallback := -1, -1
+	for i := range p.members {
+		index := (p.next + i) % len(p.members)
+		m := p.members[index]
+		if tried[m] {
+			continue
+		}
+		if now.Before(m.unhealthyUntil) {
+			if fallback < 0 || m.unhealthyUntil.Before(p.members[fallback].unhealthyUntil) {
+				fallback = index
+			}
+			continue
+		}
+		if selected < 0 || (p.strategy == PoolLeastOutstanding && m.outstanding < p.members[selected].outstanding) {
+			selected = index
+		}
+		if p.strategy == PoolRoundRobin {
+			break
+		}
+	}
+	if selected < 0 {
+		selected = fallback
+	}
+	if selected < 0 {
+		return nil
+	}
+	// Continue after the selected member, so that skipping a member in cooldown doesn't send its share to the
+	// member following it.
+	p.next = (selected + 1) % len(p.members)
+	p.members[selected].outstanding++
+	return p.members[selected]
+}
+
+// release ends an outstanding call of m and puts m into cooldown if the call failed over.
+func (p *PoolClient) release(m *poolMember, failed bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	m.outstanding--
+	if failed {
+		m.failures++
+		m.unhealthyUntil = time.Now().Add(p.cooldown)
+	}
+}
+
+func (p *PoolClient) pin(jobID string, m *poolMember) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now()
+	if now.Sub(p.lastPruned) > time.Minute {
+		for id, job := range p.jobs {
+			if now.Sub(job.submittedAt) > p.jobRetention {
+				delete(p.jobs, id)
+			}
+		}
+		p.lastPruned = now
+	}
+	p.jobs[jobID] = pinnedJob{member: m, submittedAt: now}
+}
+
+func (p *PoolClient) pinned(jobID string) *poolMember {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.jobs[jobID].member
+}
+
+// shouldFailOver reports whether a call that failed with err may be retried on another member.
+func shouldFailOver(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var inDoubtErr *JobSubmissionInDoubtError
+	if errors.As(err, &inDoubtErr) {
+		return false
+	}
+	var taskErr *TaskError
+	if errors.As(err, &taskErr) {
+		return taskErr.StatusCode >= http.StatusInternalServerError || taskErr.StatusCode == http.StatusTooManyRequests
+	}
+	var urlErr *url.Error
+	var netErr net.Error
+	return errors.As(err, &urlErr) || errors.As(err, &netErr)
+}
+
+func isNotFound(err error) bool {
+	var taskErr *TaskError
+	return errors.As(err, &taskErr) && taskErr.StatusCode == http.StatusNotFound
+}
+
+// poolCall runs call on the members until one succeeds or fails without failover.
+func poolCall[Result any](ctx context.Context, p *PoolClient, call func(m *poolMember) (Result, error)) (Result, error) {
+	tried := make(map[*poolMember]bool, len(p.members))
+	var lastErr error
+	for {
+		m := p.acquire(tried)
+		if m == nil {
+			var zero Result
+			return zero, lastErr
+		}
+		tried[m] = true
+		result, err := call(m)
+		failed := err != nil && shouldFailOver(ctx, err)
+		p.release(m, failed)
+		if !failed {
+			return result, err
+		}
+		lastErr = fmt.Errorf("pool member %s: %w", m.Name, err)
+	}
+}
+
+// jobCall runs call on the member that created the job. Unknown jobs are looked up on every member, skipping
+// members that answer 404 Not Found.
+func jobCall[Result any](ctx context.Context, p *PoolClient, jobID string, call func(c Client) (Result, error)) (Result, error) {
+	if m := p.pinned(jobID); m != nil {
+		return call(m.Client)
+	}
+	var lastErr error
+	for _, m := range p.members {
+		result, err := call(m.Client)
+		if err == nil {
+			p.pin(jobID, m)
+			return result, nil
+		}
+		if ctx.Err() != nil || (!isNotFound(err) && !shouldFailOver(ctx, err)) {
+			return result, err
+		}
+		lastErr = fmt.Errorf("pool member %s: %w", m.Name, err)
+	}
+	var zero Result
+	return zero, lastErr
+}
+
+func (p *PoolClient) AnalyzeTextLanguageDetection(ctx context.Context, input LanguageDetectionAnalysisInput, parameters LanguageDetectionTaskParameters) (*LanguageDetectionResult, error) {
+	return poolCall(ctx, p, func(m *poolMember) (*LanguageDetectionResult, error) {
+		return m.Client.AnalyzeTextLanguageDetection(ctx, input, parameters)
+	})
+}
+
+func (p *PoolClient) AnalyzeTextEntityRecognition(ctx context.Context, input MultiLanguageAnalysisInput, parameters EntitiesTaskParameters) (*EntitiesResult, error) {
+	return poolCall(ctx, p, func(m *poolMember) (*EntitiesResult, error) {
+		return m.Client.AnalyzeTextEntityRecognition(ctx, input, parameters)
+	})
+}
+
+func (p *PoolClient) AnalyzeTextKeyPhraseExtraction(ctx context.Context, input MultiLanguageAnalysisInput, parameters KeyPhraseTaskParameters) (*KeyPhraseResult, error) {
+	return poolCall(ctx, p, func(m *poolMember) (*KeyPhraseResult, error) {
+		return m.Client.AnalyzeTextKeyPhraseExtraction(ctx, input, parameters)
+	})
+}
+
+func (p *PoolClient) AnalyzeTextSentimentAnalysis(ctx context.Context, input MultiLanguageAnalysisInput, parameters SentimentAnalysisTaskParameters) (*SentimentResponse, error) {
+	return poolCall(ctx, p, func(m *poolMember) (*SentimentResponse, error) {
+		return m.Client.AnalyzeTextSentimentAnalysis(ctx, input, parameters)
+	})
+}
+
+func (p *PoolClient) AnalyzeTextPiiEntityRecognition(ctx context.Context, input MultiLanguageAnalysisInput, parameters PiiTaskParameters) (*PiiResult, error) {
+	return poolCall(ctx, p, func(m *poolMember) (*PiiResult, error) {
+		return m.Client.AnalyzeTextPiiEntityRecognition(ctx, input, parameters)
+	})
+}
+
+func (p *PoolClient) AnalyzeTextEntityLinking(ctx context.Context, input MultiLanguageAnalysisInput, parameters EntityLinkingTaskParameters) (*EntityLinkingResult, error) {
+	return poolCall(ctx, p, func(m *poolMember) (*EntityLinkingResult, error) {
+		return m.Client.AnalyzeTextEntityLinking(ctx, input, parameters)
+	})
+}
+
+func (p *PoolClient) SubmitTextAnalyticsJob(ctx context.Context, input SubmitJobRequestBody) (string, error) {
+	return poolCall(ctx, p, func(m *poolMember) (string, error) {
+		jobID, err := m.Client.SubmitTextAnalyticsJob(ctx, input)
+		if err == nil {
+			p.pin(jobID, m)
+		}
+		return jobID, err
+	})
+}
+
+func (p *PoolClient) GetTextAnalyticsJobResult(ctx context.Context, jobID string) (*JobStatusResponse, error) {
+	return jobCall(ctx, p, jobID, func(c Client) (*JobStatusResponse, error) {
+		return c.GetTextAnalyticsJobResult(ctx, jobID)
+	})
+}
+
+func (p *PoolClient) CancelTextAnalyticsJob(ctx context.Context, jobID string) (string, error) {
+	return jobCall(ctx, p, jobID, func(c Client) (string, error) {
+		return c.CancelTextAnalyticsJob(ctx, jobID)
+	})
+}
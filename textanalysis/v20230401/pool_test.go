@@ -0,0 +1,221 @@
+package v20230401_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kde713/azurelangai-go/textanalysis/v20230401"
+)
+
+type poolTestServer struct {
+	name     string
+	status   int32
+	requests int32
+}
+
+// newPoolTestServer serves a resource whose job IDs start with name. Analyze-text and job submission requests
+// fail with status unless it is 200.
+func newPoolTestServer(t *testing.T, name string, status int) (*poolTestServer, v20230401.PoolMember) {
+	s := &poolTestServer{name: name, status: int32(status)}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&s.requests, 1)
+		w.Header().Set("Content-Type", "application/json")
+		writeError := func(status int) {
+			w.WriteHeader(status)
+			_, _ = fmt.Fprintf(w, `{"error":{"code":"Error","message":"status %d"}}`, status)
+		}
+		switch {
+		case r.Method == http.MethodGet:
+			if !strings.HasPrefix(r.URL.Path, v20230401.SubmitJobAPIPath+"/"+name+"-") {
+				writeError(http.StatusNotFound)
+				return
+			}
+			_, _ = fmt.Fprintf(w, `{"jobId":"%s","status":"running","tasks":{"items":[]}}`, strings.TrimPrefix(r.URL.Path, v20230401.SubmitJobAPIPath+"/"))
+		case atomic.LoadInt32(&s.status) != http.StatusOK:
+			writeError(int(atomic.LoadInt32(&s.status)))
+		case r.URL.Path == v20230401.SubmitJobAPIPath:
+			w.Header().Set("Operation-Location", "http://"+r.Host+v20230401.SubmitJobAPIPath+"/"+name+"-job")
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			_, _ = w.Write([]byte(`{"kind":"KeyPhraseExtractionResults","results":{"documents":[],"errors":[],"modelVersion":"` + name + `"}}`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return s, v20230401.PoolMember{Name: name, Client: v20230401.NewClient(srv.URL, "key")}
+}
+
+func analyzeKeyPhrases(ctx context.Context, client v20230401.Client) (string, error) {
+	result, err := client.AnalyzeTextKeyPhraseExtraction(ctx, v20230401.MultiLanguageAnalysisInput{
+		Documents: []v20230401.MultiLanguageInput{{ID: "1", Text: "hello", Language: "en"}},
+	}, v20230401.KeyPhraseTaskParameters{})
+	if err != nil {
+		return "", err
+	}
+	return result.ModelVersion, nil
+}
+
+func TestPoolClient_RoundRobin(t *testing.T) {
+	east, eastMember := newPoolTestServer(t, "east", http.StatusOK)
+	west, westMember := newPoolTestServer(t, "west", http.StatusOK)
+	pool, err := v20230401.NewPoolClient([]v20230401.PoolMember{eastMember, westMember})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 4; i++ {
+		if _, err := analyzeKeyPhrases(context.TODO(), pool); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if east.requests != 2 || west.requests != 2 {
+		t.Errorf("Expected 2 requests each, got %d and %d", east.requests, west.requests)
+	}
+}
+
+func TestPoolClient_RoundRobinDuringCooldown(t *testing.T) {
+	_, eastMember := newPoolTestServer(t, "east", http.StatusServiceUnavailable)
+	west, westMember := newPoolTestServer(t, "west", http.StatusOK)
+	north, northMember := newPoolTestServer(t, "north", http.StatusOK)
+	pool, err := v20230401.NewPoolClient([]v20230401.PoolMember{eastMember, westMember, northMember}, v20230401.WithPoolCooldown(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 30; i++ {
+		if _, err := analyzeKeyPhrases(context.TODO(), pool); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The first call fails over from east to west.
+	if west.requests != 15 || north.requests != 15 {
+		t.Errorf("Expected 15 requests each, got %d and %d", west.requests, north.requests)
+	}
+}
+
+func TestPoolClient_Failover(t *testing.T) {
+	east, eastMember := newPoolTestServer(t, "east", http.StatusInternalServerError)
+	west, westMember := newPoolTestServer(t, "west", http.StatusOK)
+	pool, err := v20230401.NewPoolClient([]v20230401.PoolMember{eastMember, westMember}, v20230401.WithPoolCooldown(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		served, err := analyzeKeyPhrases(context.TODO(), pool)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if served != "west" {
+			t.Errorf("Expected west, got %s", served)
+		}
+	}
+	if east.requests != 1 || west.requests != 3 {
+		t.Errorf("Expected east to be skipped after one failure, got %d and %d requests", east.requests, west.requests)
+	}
+	status := pool.Status()
+	if status[0].Healthy || status[0].Failures != 1 || !status[1].Healthy {
+		t.Errorf("Expected east to be unhealthy, got %+v", status)
+	}
+
+	// All members failing: the error of the last one is returned.
+	atomic.StoreInt32(&west.status, http.StatusServiceUnavailable)
+	_, err = analyzeKeyPhrases(context.TODO(), pool)
+	var taskErr *v20230401.TaskError
+	if !errors.As(err, &taskErr) {
+		t.Fatalf("Expected TaskError, got %v", err)
+	}
+}
+
+func TestPoolClient_NoFailoverOnClientError(t *testing.T) {
+	east, eastMember := newPoolTestServer(t, "east", http.StatusBadRequest)
+	west, westMember := newPoolTestServer(t, "west", http.StatusOK)
+	pool, err := v20230401.NewPoolClient([]v20230401.PoolMember{eastMember, westMember})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := analyzeKeyPhrases(context.TODO(), pool); err == nil {
+		t.Fatal("Expected error")
+	}
+	if east.requests != 1 || west.requests != 0 {
+		t.Errorf("Expected no failover, got %d and %d requests", east.requests, west.requests)
+	}
+}
+
+func TestPoolClient_JobPinning(t *testing.T) {
+	east, eastMember := newPoolTestServer(t, "east", http.StatusOK)
+	west, westMember := newPoolTestServer(t, "west", http.StatusOK)
+	pool, err := v20230401.NewPoolClient([]v20230401.PoolMember{eastMember, westMember})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var jobIDs []string
+	for i := 0; i < 2; i++ {
+		jobID, err := pool.SubmitTextAnalyticsJob(context.TODO(), newTestJobRequest(t, "hello"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		jobIDs = append(jobIDs, jobID)
+	}
+	for i := 0; i < 3; i++ {
+		for _, jobID := range jobIDs {
+			job, err := pool.GetTextAnalyticsJobResult(context.TODO(), jobID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if job.JobID != jobID {
+				t.Errorf("Expected %s, got %s", jobID, job.JobID)
+			}
+		}
+	}
+	if east.requests != 4 || west.requests != 4 {
+		t.Errorf("Expected 4 requests each, got %d and %d", east.requests, west.requests)
+	}
+}
+
+func TestPoolClient_UnknownJob(t *testing.T) {
+	east, eastMember := newPoolTestServer(t, "east", http.StatusOK)
+	west, westMember := newPoolTestServer(t, "west", http.StatusOK)
+	pool, err := v20230401.NewPoolClient([]v20230401.PoolMember{eastMember, westMember})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := pool.GetTextAnalyticsJobResult(context.TODO(), "west-job"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if east.requests != 1 || west.requests != 2 {
+		t.Errorf("Expected the job to be looked up once, got %d and %d requests", east.requests, west.requests)
+	}
+	if _, err := pool.GetTextAnalyticsJobResult(context.TODO(), "north-job"); err == nil {
+		t.Error("Expected error for a job unknown to every member")
+	}
+}
+
+func TestPoolClient_SubmitInDoubt(t *testing.T) {
+	east, eastMember := newPoolTestServer(t, "east", http.StatusInternalServerError)
+	west, westMember := newPoolTestServer(t, "west", http.StatusOK)
+	pool, err := v20230401.NewPoolClient([]v20230401.PoolMember{eastMember, westMember})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = pool.SubmitTextAnalyticsJob(context.TODO(), newTestJobRequest(t, "hello"))
+	var inDoubtErr *v20230401.JobSubmissionInDoubtError
+	if !errors.As(err, &inDoubtErr) {
+		t.Fatalf("Expected JobSubmissionInDoubtError, got %v", err)
+	}
+	if east.requests != 1 || west.requests != 0 {
+		t.Errorf("Expected no failover, got %d and %d requests", east.requests, west.requests)
+	}
+}
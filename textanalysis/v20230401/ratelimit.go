@@ -0,0 +1,178 @@
+package v20230401
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// TextRecordCharacters is the number of characters of a document billed as one text record.
+const TextRecordCharacters = 1000
+
+// RateLimit configures a RateLimiter. Zero fields are not limited.
+type RateLimit struct {
+	// RequestsPerSecond Maximum sustained number of requests per second.
+	RequestsPerSecond float64
+	// RequestsPerMinute Maximum number of requests per minute.
+	RequestsPerMinute float64
+	// TextRecordsPerMinute Maximum number of text records per minute. Every document counts as one text record per
+	// started TextRecordCharacters characters, once per task.
+	TextRecordsPerMinute float64
+}
+
+var (
+	// RateLimitF0 matches the request rate of the free (F0) pricing tier.
+	RateLimitF0 = RateLimit{RequestsPerMinute: 100}
+	// RateLimitS matches the request rate of the standard (S) pricing tier.
+	RateLimitS = RateLimit{RequestsPerSecond: 100, RequestsPerMinute: 1000}
+)
+
+// tokenBucket holds up to capacity tokens and refills at rate tokens per second. Its level may go negative, which
+// is the debt that later callers have to wait for.
+type tokenBucket struct {
+	rate     float64
+	capacity float64
+	tokens   float64
+	last     time.Time
+}
+
+func newTokenBucket(rate float64, capacity float64, now time.Time) *tokenBucket {
+	return &tokenBucket{rate: rate, capacity: capacity, tokens: capacity, last: now}
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	b.tokens = math.Min(b.capacity, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+}
+
+// wait returns how long to wait until n tokens are available.
+func (b *tokenBucket) wait(n float64) time.Duration {
+	if b.tokens >= n {
+		return 0
+	}
+	// Requests larger than the capacity proceed once the bucket is full.
+	missing := math.Min(n, b.capacity) - b.tokens
+	if missing <= 0 {
+		return 0
+	}
+	return time.Duration(missing / b.rate * float64(time.Second))
+}
+
+// RateLimiter paces requests with token buckets so that they stay within a RateLimit. It is safe for concurrent
+// use; share one limiter between all clients using the same resource.
+type RateLimiter struct {
+	mu          sync.Mutex
+	requests    []*tokenBucket
+	textRecords *tokenBucket
+}
+
+// NewRateLimiter creates a RateLimiter for limit. Buckets start full, so a burst of up to one second (or minute)
+// worth of requests is allowed.
+func NewRateLimiter(limit RateLimit) *RateLimiter {
+	now := time.Now()
+	l := &RateLimiter{}
+	if limit.RequestsPerSecond > 0 {
+		l.requests = append(l.requests, newTokenBucket(limit.RequestsPerSecond, math.Max(1, limit.RequestsPerSecond), now))
+	}
+	if limit.RequestsPerMinute > 0 {
+		l.requests = append(l.requests, newTokenBucket(limit.RequestsPerMinute/60, math.Max(1, limit.RequestsPerMinute), now))
+	}
+	if limit.TextRecordsPerMinute > 0 {
+		l.textRecords = newTokenBucket(limit.TextRecordsPerMinute/60, math.Max(1, limit.TextRecordsPerMinute), now)
+	}
+	return l
+}
+
+// Wait blocks until a request of textRecords text records may be sent, or returns an error if ctx is done first or
+// its deadline is too close. The tokens are taken when Wait returns successfully.
+func (l *RateLimiter) Wait(ctx context.Context, textRecords int) error {
+	for {
+		delay := l.reserve(float64(textRecords))
+		if delay == 0 {
+			return nil
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return fmt.Errorf("rate limit wait of %s exceeds context deadline: %w", delay, context.DeadlineExceeded)
+		}
+		if err := sleepContext(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// reserve takes the tokens of a request if all buckets have them, or returns how long to wait otherwise.
+func (l *RateLimiter) reserve(textRecords float64) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	var delay time.Duration
+	for _, b := range l.requests {
+		b.refill(now)
+		if d := b.wait(1); d > delay {
+			delay = d
+		}
+	}
+	if l.textRecords != nil && textRecords > 0 {
+		l.textRecords.refill(now)
+		if d := l.textRecords.wait(textRecords); d > delay {
+			delay = d
+		}
+	}
+	if delay > 0 {
+		return delay
+	}
+	for _, b := range l.requests {
+		b.tokens--
+	}
+	if l.textRecords != nil {
+		l.textRecords.tokens -= textRecords
+	}
+	return 0
+}
+
+// textRecordCounter is implemented by request bodies to report the number of text records they are billed for.
+type textRecordCounter interface {
+	textRecords() int
+}
+
+func textRecordsOf(text string) int {
+	return (utf8.RuneCountInString(text) + TextRecordCharacters - 1) / TextRecordCharacters
+}
+
+func (i LanguageDetectionAnalysisInput) textRecords() int {
+	records := 0
+	for _, doc := range i.Documents {
+		records += textRecordsOf(doc.Text)
+	}
+	return records
+}
+
+func (i MultiLanguageAnalysisInput) textRecords() int {
+	records := 0
+	for _, doc := range i.Documents {
+		records += textRecordsOf(doc.Text)
+	}
+	return records
+}
+
+func (b RequestBody[AnalysisInput, Parameters]) textRecords() int {
+	if counter, ok := any(b.AnalysisInput).(textRecordCounter); ok {
+		return counter.textRecords()
+	}
+	return 0
+}
+
+func (b SubmitJobRequestBody) textRecords() int {
+	return b.AnalysisInput.textRecords() * len(b.Tasks)
+}
+
+// requestTextRecords returns the number of text records of a request body, or zero for requests without documents.
+func requestTextRecords(body interface{}) int {
+	if counter, ok := body.(textRecordCounter); ok {
+		return counter.textRecords()
+	}
+	return 0
+}
@@ -0,0 +1,95 @@
+package v20230401_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kde713/azurelangai-go/textanalysis/v20230401"
+)
+
+func TestRateLimiter_RequestsPerSecond(t *testing.T) {
+	limiter := v20230401.NewRateLimiter(v20230401.RateLimit{RequestsPerSecond: 20})
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 30; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := limiter.Wait(context.TODO(), 0); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	// 20 requests pass immediately, the remaining 10 at 20 per second.
+	if elapsed := time.Since(start); elapsed < 400*time.Millisecond || elapsed > 2*time.Second {
+		t.Errorf("Expected about 500ms, took %s", elapsed)
+	}
+}
+
+func TestRateLimiter_TextRecords(t *testing.T) {
+	limiter := v20230401.NewRateLimiter(v20230401.RateLimit{TextRecordsPerMinute: 600})
+
+	start := time.Now()
+	if err := limiter.Wait(context.TODO(), 600); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("Expected full budget to be available, took %s", elapsed)
+	}
+	if err := limiter.Wait(context.TODO(), 3); err != nil {
+		t.Fatal(err)
+	}
+	// 3 text records at 10 per second.
+	if elapsed := time.Since(start); elapsed < 250*time.Millisecond || elapsed > 2*time.Second {
+		t.Errorf("Expected about 300ms, took %s", elapsed)
+	}
+}
+
+func TestRateLimiter_Deadline(t *testing.T) {
+	limiter := v20230401.NewRateLimiter(v20230401.RateLimit{RequestsPerMinute: 1})
+	if err := limiter.Wait(context.TODO(), 0); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := limiter.Wait(ctx, 0)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("Expected to fail immediately, took %s", elapsed)
+	}
+}
+
+func TestWithRateLimiter(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jobId":"job-1","status":"running","tasks":{"items":[]}}`))
+	}))
+	defer srv.Close()
+	// Clients of the same resource share a limiter.
+	limiter := v20230401.NewRateLimiter(v20230401.RateLimit{RequestsPerSecond: 10})
+	clients := []v20230401.Client{
+		v20230401.NewClient(srv.URL, "key", v20230401.WithRateLimiter(limiter)),
+		v20230401.NewClient(srv.URL, "key", v20230401.WithRateLimiter(limiter)),
+	}
+
+	start := time.Now()
+	for i := 0; i < 14; i++ {
+		if _, err := clients[i%2].GetTextAnalyticsJobResult(context.TODO(), "job-1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 300*time.Millisecond {
+		t.Errorf("Expected requests to be paced, took %s", elapsed)
+	}
+}
@@ -0,0 +1,159 @@
+package v20230401
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// RetryPolicy controls how failed requests are retried. Idempotent requests are retried on transport errors and
+// on 429 Too Many Requests, 500 Internal Server Error, 502 Bad Gateway and 503 Service Unavailable. Job
+// submissions are retried on 429 Too Many Requests only, since the job may already have been accepted otherwise.
+//
+// The delay before a retry is taken from the Retry-After header (or the retry-after-ms and x-ms-retry-after-ms
+// headers) when the service sends one. Otherwise it is drawn uniformly from [0, min(MaxDelay, BaseDelay*2^n))
+// ("full jitter"), where n is the number of retries so far. A request whose Retry-After exceeds MaxDelay is not
+// retried; its error is returned instead, as a retry sent earlier than requested would be throttled again.
+type RetryPolicy struct {
+	// MaxRetries Maximum number of retries. Zero disables retries.
+	MaxRetries int
+	// BaseDelay Backoff delay before the first retry, doubled for every further retry. Defaults to 1 second.
+	BaseDelay time.Duration
+	// MaxDelay Upper bound of the delay before a retry, whether backed off or requested by Retry-After. Defaults to
+	// 30 seconds.
+	MaxDelay time.Duration
+	// MaxElapsed Upper bound of the total time spent on a request including retries. A retry whose delay would
+	// exceed it is not attempted. Zero means no limit.
+	MaxElapsed time.Duration
+	// OnRetry Called before waiting for each retry.
+	OnRetry func(RetryAttempt)
+
+	throttledOnly bool
+}
+
+// RetryAttempt describes a retry that is about to happen.
+type RetryAttempt struct {
+	// Attempt Number of the upcoming retry, starting at 1.
+	Attempt int
+	// StatusCode HTTP status of the failed attempt, or zero on a transport error.
+	StatusCode int
+	// Err Transport error of the failed attempt, if any.
+	Err error
+	// RetryAfter Delay requested by the service, or zero if it sent none.
+	RetryAfter time.Duration
+	// Delay Time to wait before the retry.
+	Delay time.Duration
+	// Elapsed Time spent on the request so far.
+	Elapsed time.Duration
+}
+
+const (
+	defaultRetryBaseDelay = 1 * time.Second
+	defaultRetryMaxDelay  = 30 * time.Second
+)
+
+var (
+	jitterMu   sync.Mutex
+	jitterRand = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+func isRetryableStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable:
+		return true
+	}
+	return false
+}
+
+// backoff returns the full-jitter delay before retry number attempt (starting at 1).
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	base, maxDelay := p.BaseDelay, p.maxDelay()
+	if base <= 0 {
+		base = defaultRetryBaseDelay
+	}
+	ceiling := maxDelay
+	if shift := attempt - 1; shift < 32 && base<<shift > 0 && base<<shift < maxDelay {
+		ceiling = base << shift
+	}
+	jitterMu.Lock()
+	defer jitterMu.Unlock()
+	return time.Duration(jitterRand.Int63n(int64(ceiling) + 1))
+}
+
+// maxDelay returns MaxDelay or its default.
+func (p RetryPolicy) maxDelay() time.Duration {
+	if p.MaxDelay <= 0 {
+		return defaultRetryMaxDelay
+	}
+	return p.MaxDelay
+}
+
+// nextRetry reports whether the outcome of attempt should be retried and how long to wait before doing so.
+func (p RetryPolicy) nextRetry(attempt int, start time.Time, resp *resty.Response, err error) (RetryAttempt, bool) {
+	if attempt > p.MaxRetries {
+		return RetryAttempt{}, false
+	}
+	retry := RetryAttempt{Attempt: attempt, Err: err, Elapsed: time.Since(start)}
+	switch {
+	case err != nil:
+		// Errors caused by the context of the call are not retried by the caller; a per-attempt timeout is.
+		if p.throttledOnly {
+			return RetryAttempt{}, false
+		}
+	case resp.StatusCode() == http.StatusTooManyRequests || (!p.throttledOnly && isRetryableStatus(resp.StatusCode())):
+		retry.StatusCode = resp.StatusCode()
+		retry.RetryAfter = parseRetryAfter(resp.Header(), time.Now())
+	default:
+		return RetryAttempt{}, false
+	}
+	retry.Delay = retry.RetryAfter
+	if retry.Delay == 0 {
+		retry.Delay = p.backoff(attempt)
+	} else if retry.Delay > p.maxDelay() {
+		return RetryAttempt{}, false
+	}
+	if p.MaxElapsed > 0 && retry.Elapsed+retry.Delay > p.MaxElapsed {
+		return RetryAttempt{}, false
+	}
+	return retry, true
+}
+
+// parseRetryAfter reads the delay requested by the service. Retry-After holds either seconds or an HTTP-date.
+func parseRetryAfter(header http.Header, now time.Time) time.Duration {
+	for _, name := range []string{"retry-after-ms", "x-ms-retry-after-ms"} {
+		if ms, err := strconv.ParseInt(header.Get(name), 10, 64); err == nil && ms > 0 {
+			return time.Duration(ms) * time.Millisecond
+		}
+	}
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if seconds <= 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+	return 0
+}
+
+// sleepContext waits for d or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,209 @@
+package v20230401_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kde713/azurelangai-go/textanalysis/v20230401"
+)
+
+// failureCodes are the error codes of the failure responses of newFlakyServer by status.
+var failureCodes = map[int]v20230401.ErrorCode{
+	http.StatusBadRequest:          v20230401.ErrorCodeInvalidArgument,
+	http.StatusTooManyRequests:     v20230401.ErrorCodeTooManyRequests,
+	http.StatusInternalServerError: v20230401.ErrorCodeInternalServerError,
+	http.StatusServiceUnavailable:  v20230401.ErrorCodeServiceUnavailable,
+}
+
+// newFlakyServer fails the first failures requests with status, the matching error code and failureHeader, then
+// answers with body and header, defaulting to a succeeded job status. Every response has the apim-request-id
+// "request-<n>".
+func newFlakyServer(t *testing.T, failures int32, status int, failureHeader http.Header, body string, header http.Header) (*httptest.Server, *int32) {
+	if body == "" {
+		body = `{"jobId":"job-1","status":"succeeded","tasks":{"items":[]}}`
+	}
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&requests, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("apim-request-id", fmt.Sprintf("request-%d", n))
+		if n <= failures {
+			for name, values := range failureHeader {
+				w.Header()[name] = values
+			}
+			w.WriteHeader(status)
+			_, _ = fmt.Fprintf(w, `{"error":{"code":%q,"message":%q}}`, failureCodes[status], http.StatusText(status))
+			return
+		}
+		for name, values := range header {
+			w.Header()[name] = values
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	srv, requests := newFlakyServer(t, 3, http.StatusServiceUnavailable, nil, "", nil)
+	var attempts []v20230401.RetryAttempt
+	client := v20230401.NewClient(srv.URL, "key", v20230401.WithRetryPolicy(v20230401.RetryPolicy{
+		MaxRetries: 3,
+		BaseDelay:  10 * time.Millisecond,
+		MaxDelay:   15 * time.Millisecond,
+		OnRetry: func(attempt v20230401.RetryAttempt) {
+			attempts = append(attempts, attempt)
+		},
+	}))
+
+	if _, err := client.GetTextAnalyticsJobResult(context.TODO(), "job-1"); err != nil {
+		t.Fatal(err)
+	}
+	if got := atomic.LoadInt32(requests); got != 4 {
+		t.Errorf("Expected 4 requests, got %d", got)
+	}
+	if len(attempts) != 3 {
+		t.Fatalf("Expected 3 retries, got %d", len(attempts))
+	}
+	for i, attempt := range attempts {
+		if attempt.Attempt != i+1 {
+			t.Errorf("Expected attempt %d, got %d", i+1, attempt.Attempt)
+		}
+		if attempt.StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("Expected status 503, got %d", attempt.StatusCode)
+		}
+		if attempt.Delay < 0 || attempt.Delay > 15*time.Millisecond {
+			t.Errorf("Expected delay within [0, 15ms], got %s", attempt.Delay)
+		}
+	}
+}
+
+func TestRetryPolicy_MaxRetries(t *testing.T) {
+	srv, requests := newFlakyServer(t, 10, http.StatusTooManyRequests, nil, "", nil)
+	client := v20230401.NewClient(srv.URL, "key", v20230401.WithRetryCount(2, time.Millisecond, time.Millisecond))
+
+	_, err := client.GetTextAnalyticsJobResult(context.TODO(), "job-1")
+	var taskErr *v20230401.TaskError
+	if !errors.As(err, &taskErr) {
+		t.Fatalf("Expected TaskError, got %v", err)
+	}
+	if got := atomic.LoadInt32(requests); got != 3 {
+		t.Errorf("Expected 3 requests, got %d", got)
+	}
+}
+
+func TestRetryPolicy_NotRetryable(t *testing.T) {
+	srv, requests := newFlakyServer(t, 1, http.StatusBadRequest, nil, "", nil)
+	client := v20230401.NewClient(srv.URL, "key", v20230401.WithRetryCount(2, time.Millisecond, time.Millisecond))
+
+	if _, err := client.GetTextAnalyticsJobResult(context.TODO(), "job-1"); err == nil {
+		t.Fatal("Expected error")
+	}
+	if got := atomic.LoadInt32(requests); got != 1 {
+		t.Errorf("Expected 1 request, got %d", got)
+	}
+}
+
+func TestRetryPolicy_RetryAfter(t *testing.T) {
+	testCases := []struct {
+		name     string
+		header   http.Header
+		minDelay time.Duration
+		maxDelay time.Duration
+	}{
+		{
+			name:     "Seconds",
+			header:   http.Header{"Retry-After": {"7"}},
+			minDelay: 7 * time.Second,
+			maxDelay: 7 * time.Second,
+		},
+		{
+			name:     "HTTPDate",
+			header:   http.Header{"Retry-After": {time.Now().Add(30 * time.Second).UTC().Format(http.TimeFormat)}},
+			minDelay: 28 * time.Second,
+			maxDelay: 30 * time.Second,
+		},
+		{
+			name:     "Milliseconds",
+			header:   http.Header{"Retry-After-Ms": {"250"}, "Retry-After": {"1"}},
+			minDelay: 250 * time.Millisecond,
+			maxDelay: 250 * time.Millisecond,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv, _ := newFlakyServer(t, 1, http.StatusTooManyRequests, tc.header, "", nil)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			var attempt v20230401.RetryAttempt
+			client := v20230401.NewClient(srv.URL, "key", v20230401.WithRetryPolicy(v20230401.RetryPolicy{
+				MaxRetries: 1,
+				OnRetry: func(a v20230401.RetryAttempt) {
+					attempt = a
+					cancel() // Don't actually wait.
+				},
+			}))
+
+			_, err := client.GetTextAnalyticsJobResult(ctx, "job-1")
+			if !errors.Is(err, context.Canceled) {
+				t.Fatalf("Expected context.Canceled, got %v", err)
+			}
+			if attempt.RetryAfter < tc.minDelay || attempt.RetryAfter > tc.maxDelay {
+				t.Errorf("Expected RetryAfter within [%s, %s], got %s", tc.minDelay, tc.maxDelay, attempt.RetryAfter)
+			}
+			if attempt.Delay != attempt.RetryAfter {
+				t.Errorf("Expected delay %s, got %s", attempt.RetryAfter, attempt.Delay)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_MaxElapsed(t *testing.T) {
+	srv, requests := newFlakyServer(t, 1, http.StatusTooManyRequests, http.Header{"Retry-After": {"60"}}, "", nil)
+	retried := false
+	client := v20230401.NewClient(srv.URL, "key", v20230401.WithRetryPolicy(v20230401.RetryPolicy{
+		MaxRetries: 3,
+		MaxDelay:   time.Minute,
+		MaxElapsed: 10 * time.Second,
+		OnRetry: func(v20230401.RetryAttempt) {
+			retried = true
+		},
+	}))
+
+	start := time.Now()
+	if _, err := client.GetTextAnalyticsJobResult(context.TODO(), "job-1"); err == nil {
+		t.Fatal("Expected error")
+	}
+	if retried {
+		t.Error("Expected no retry beyond MaxElapsed")
+	}
+	if got := atomic.LoadInt32(requests); got != 1 {
+		t.Errorf("Expected 1 request, got %d", got)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected to give up immediately, took %s", elapsed)
+	}
+}
+
+func TestRetryPolicy_RetryAfterAboveMaxDelay(t *testing.T) {
+	srv, requests := newFlakyServer(t, 1, http.StatusTooManyRequests, http.Header{"Retry-After": {"60"}}, "", nil)
+	client := v20230401.NewClient(srv.URL, "key", v20230401.WithRetryCount(3, time.Millisecond, 10*time.Second))
+
+	start := time.Now()
+	_, err := client.GetTextAnalyticsJobResult(context.TODO(), "job-1")
+	if !errors.Is(err, v20230401.ErrTooManyRequests) {
+		t.Fatalf("Expected ErrTooManyRequests, got %v", err)
+	}
+	if got := atomic.LoadInt32(requests); got != 1 {
+		t.Errorf("Expected 1 request, got %d", got)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected to give up immediately, took %s", elapsed)
+	}
+}
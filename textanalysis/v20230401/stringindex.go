@@ -0,0 +1,122 @@
+package v20230401
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+// ErrInvalidOffset is returned when an offset does not fall on a boundary of the text in the requested unit.
+var ErrInvalidOffset = errors.New("invalid text offset")
+
+// StringIndexType specifies the unit of the offsets and lengths returned by the service.
+// For additional information see https://aka.ms/text-analytics-offsets.
+type StringIndexType string
+
+const (
+	// StringIndexTypeTextElementV8 counts text elements (grapheme clusters) according to Unicode v8.0.0. This is the service default.
+	StringIndexTypeTextElementV8 StringIndexType = "TextElement_v8"
+	// StringIndexTypeUnicodeCodePoint counts Unicode code points, which is the Go rune count.
+	StringIndexTypeUnicodeCodePoint StringIndexType = "UnicodeCodePoint"
+	// StringIndexTypeUtf16CodeUnit counts UTF-16 code units, as used by JavaScript, Java and .NET strings.
+	StringIndexTypeUtf16CodeUnit StringIndexType = "Utf16CodeUnit"
+)
+
+// Length returns the length of s in units of t. An empty StringIndexType is treated as StringIndexTypeTextElementV8.
+func (t StringIndexType) Length(s string) int {
+	switch t {
+	case StringIndexTypeUnicodeCodePoint:
+		return utf8.RuneCountInString(s)
+	case StringIndexTypeUtf16CodeUnit:
+		n := 0
+		for _, r := range s {
+			n += utf16Len(r)
+		}
+		return n
+	default:
+		return countTextElements(s)
+	}
+}
+
+// ByteOffset converts an offset returned by the service into a byte offset into text.
+func (t StringIndexType) ByteOffset(text string, offset int) (int, error) {
+	if offset < 0 {
+		return 0, fmt.Errorf("%w: offset %d is negative", ErrInvalidOffset, offset)
+	}
+	i, units := 0, 0
+	for units < offset {
+		if i >= len(text) {
+			return 0, fmt.Errorf("%w: offset %d is beyond the end of the text", ErrInvalidOffset, offset)
+		}
+		n, size := t.next(text[i:])
+		i += size
+		units += n
+	}
+	if units != offset {
+		return 0, fmt.Errorf("%w: offset %d splits a UTF-16 surrogate pair", ErrInvalidOffset, offset)
+	}
+	return i, nil
+}
+
+// ServiceOffset converts a byte offset into text into an offset in units of t, as expected by the service.
+// byteOffset must fall on a boundary of the unit, e.g. not inside a grapheme cluster for StringIndexTypeTextElementV8.
+func (t StringIndexType) ServiceOffset(text string, byteOffset int) (int, error) {
+	if byteOffset < 0 || byteOffset > len(text) {
+		return 0, fmt.Errorf("%w: byte offset %d is out of range", ErrInvalidOffset, byteOffset)
+	}
+	i, units := 0, 0
+	for i < byteOffset {
+		n, size := t.next(text[i:])
+		i += size
+		units += n
+	}
+	if i != byteOffset {
+		return 0, fmt.Errorf("%w: byte offset %d is not on a %s boundary", ErrInvalidOffset, byteOffset, t.name())
+	}
+	return units, nil
+}
+
+// Slice returns the part of text identified by an offset and a length returned by the service,
+// e.g. Entity.Offset and Entity.Length. It returns an error instead of panicking when they do not fit the text.
+func (t StringIndexType) Slice(text string, offset int, length int) (string, error) {
+	if length < 0 {
+		return "", fmt.Errorf("%w: length %d is negative", ErrInvalidOffset, length)
+	}
+	start, err := t.ByteOffset(text, offset)
+	if err != nil {
+		return "", err
+	}
+	n, err := t.ByteOffset(text[start:], length)
+	if err != nil {
+		return "", err
+	}
+	return text[start : start+n], nil
+}
+
+// next returns the size in units of t and in bytes of the first unit of s.
+func (t StringIndexType) next(s string) (units int, size int) {
+	switch t {
+	case StringIndexTypeUnicodeCodePoint:
+		_, size = utf8.DecodeRuneInString(s)
+		return 1, size
+	case StringIndexTypeUtf16CodeUnit:
+		r, size := utf8.DecodeRuneInString(s)
+		return utf16Len(r), size
+	default:
+		return 1, textElementLen(s)
+	}
+}
+
+func (t StringIndexType) name() string {
+	if t == "" {
+		return string(StringIndexTypeTextElementV8)
+	}
+	return string(t)
+}
+
+func utf16Len(r rune) int {
+	if r >= 0x10000 {
+		return 2
+	}
+	return 1
+}
@@ -0,0 +1,89 @@
+package v20230401_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kde713/azurelangai-go/textanalysis/v20230401"
+)
+
+func TestStringIndexType_Slice(t *testing.T) {
+	// "👍🏽" is one text element made of two code points (four UTF-16 code units), "각" is three conjoining jamo,
+	// and "🇰🇷" is a regional indicator pair.
+	text := "👍🏽 각 한국 🇰🇷 café"
+	tests := []struct {
+		stringIndexType v20230401.StringIndexType
+		offset          int
+		length          int
+		want            string
+	}{
+		{v20230401.StringIndexTypeTextElementV8, 0, 1, "👍🏽"},
+		{v20230401.StringIndexTypeTextElementV8, 2, 1, "각"},
+		{v20230401.StringIndexTypeTextElementV8, 4, 2, "한국"},
+		{v20230401.StringIndexTypeTextElementV8, 7, 1, "🇰🇷"},
+		{v20230401.StringIndexTypeTextElementV8, 9, 4, "café"},
+		{"", 4, 2, "한국"},
+		{v20230401.StringIndexTypeUnicodeCodePoint, 0, 2, "👍🏽"},
+		{v20230401.StringIndexTypeUnicodeCodePoint, 7, 2, "한국"},
+		{v20230401.StringIndexTypeUnicodeCodePoint, 10, 2, "🇰🇷"},
+		{v20230401.StringIndexTypeUtf16CodeUnit, 0, 4, "👍🏽"},
+		{v20230401.StringIndexTypeUtf16CodeUnit, 9, 2, "한국"},
+		{v20230401.StringIndexTypeUtf16CodeUnit, 12, 4, "🇰🇷"},
+		{v20230401.StringIndexTypeUtf16CodeUnit, 17, 4, "café"},
+	}
+	for _, tt := range tests {
+		got, err := tt.stringIndexType.Slice(text, tt.offset, tt.length)
+		if err != nil {
+			t.Errorf("%s Slice(%d, %d): %v", tt.stringIndexType, tt.offset, tt.length, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s Slice(%d, %d): expected %q, got %q", tt.stringIndexType, tt.offset, tt.length, tt.want, got)
+		}
+	}
+}
+
+func TestStringIndexType_SliceInvalid(t *testing.T) {
+	text := "😀 ok"
+	for _, tt := range []struct {
+		stringIndexType v20230401.StringIndexType
+		offset, length  int
+	}{
+		{v20230401.StringIndexTypeUtf16CodeUnit, 1, 1},    // inside a surrogate pair
+		{v20230401.StringIndexTypeUnicodeCodePoint, 3, 2}, // beyond the end
+		{v20230401.StringIndexTypeTextElementV8, -1, 1},
+	} {
+		if _, err := tt.stringIndexType.Slice(text, tt.offset, tt.length); !errors.Is(err, v20230401.ErrInvalidOffset) {
+			t.Errorf("%s Slice(%d, %d): expected ErrInvalidOffset, got %v", tt.stringIndexType, tt.offset, tt.length, err)
+		}
+	}
+}
+
+func TestStringIndexType_ServiceOffset(t *testing.T) {
+	text := "안녕 👩‍👩‍👧 hi"
+	byteOffset := len("안녕 👩‍👩‍👧 ")
+	for stringIndexType, want := range map[v20230401.StringIndexType]int{
+		v20230401.StringIndexTypeTextElementV8:    5,
+		v20230401.StringIndexTypeUnicodeCodePoint: 9,
+		v20230401.StringIndexTypeUtf16CodeUnit:    12,
+	} {
+		got, err := stringIndexType.ServiceOffset(text, byteOffset)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("%s: expected offset %d, got %d", stringIndexType, want, got)
+		}
+		back, err := stringIndexType.ByteOffset(text, got)
+		if err != nil || back != byteOffset {
+			t.Errorf("%s: expected byte offset %d, got %d (%v)", stringIndexType, byteOffset, back, err)
+		}
+		if got := stringIndexType.Length(text); got != want+2 {
+			t.Errorf("%s: expected length %d, got %d", stringIndexType, want+2, got)
+		}
+	}
+	// Inside the ZWJ sequence is not a text element boundary.
+	if _, err := v20230401.StringIndexTypeTextElementV8.ServiceOffset(text, len("안녕 👩")); !errors.Is(err, v20230401.ErrInvalidOffset) {
+		t.Errorf("Expected ErrInvalidOffset, got %v", err)
+	}
+}
@@ -0,0 +1,118 @@
+package v20230401
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// This file implements the subset of the Unicode extended grapheme cluster rules (UAX #29) that matters for the
+// service's TextElement_v8 offsets: CR LF, combining marks, variation selectors, emoji modifiers and ZWJ sequences,
+// regional indicator pairs and conjoining Hangul jamo.
+
+const zeroWidthJoiner = '\u200d'
+
+type hangulType int
+
+const (
+	hangulNone hangulType = iota
+	hangulL
+	hangulV
+	hangulT
+	hangulLV
+	hangulLVT
+)
+
+func hangulTypeOf(r rune) hangulType {
+	switch {
+	case r >= 0x1100 && r <= 0x115F, r >= 0xA960 && r <= 0xA97C:
+		return hangulL
+	case r >= 0x1160 && r <= 0x11A7, r >= 0xD7B0 && r <= 0xD7C6:
+		return hangulV
+	case r >= 0x11A8 && r <= 0x11FF, r >= 0xD7CB && r <= 0xD7FB:
+		return hangulT
+	case r >= 0xAC00 && r <= 0xD7A3:
+		if (r-0xAC00)%28 == 0 {
+			return hangulLV
+		}
+		return hangulLVT
+	}
+	return hangulNone
+}
+
+func isGraphemeExtend(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc) ||
+		r == zeroWidthJoiner ||
+		(r >= 0xFE00 && r <= 0xFE0F) || // variation selectors
+		(r >= 0x1F3FB && r <= 0x1F3FF) || // emoji skin tone modifiers
+		(r >= 0xE0020 && r <= 0xE007F) || // tags
+		(r >= 0xE0100 && r <= 0xE01EF) // variation selectors supplement
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1F1E6 && r <= 0x1F1FF
+}
+
+func isPictographic(r rune) bool {
+	return (r >= 0x1F000 && r <= 0x1FAFF) || (r >= 0x2600 && r <= 0x27BF) || unicode.Is(unicode.So, r)
+}
+
+func isGraphemeControl(r rune) bool {
+	return r == '\r' || r == '\n' || (unicode.IsControl(r) && r != zeroWidthJoiner)
+}
+
+// textElementLen returns the number of bytes of the first text element (grapheme cluster) of s.
+func textElementLen(s string) int {
+	if s == "" {
+		return 0
+	}
+	prev, n := utf8.DecodeRuneInString(s)
+	if prev == '\r' && len(s) > n && s[n] == '\n' {
+		return n + 1
+	}
+	if isGraphemeControl(prev) {
+		return n
+	}
+	riCount := 0
+	if isRegionalIndicator(prev) {
+		riCount = 1
+	}
+	pictographic := isPictographic(prev)
+	for n < len(s) {
+		r, size := utf8.DecodeRuneInString(s[n:])
+		if isGraphemeControl(r) {
+			break
+		}
+		join := false
+		switch prevType, curType := hangulTypeOf(prev), hangulTypeOf(r); {
+		case isGraphemeExtend(r):
+			join = true
+		case prevType == hangulL && (curType == hangulL || curType == hangulV || curType == hangulLV || curType == hangulLVT):
+			join = true
+		case (prevType == hangulLV || prevType == hangulV) && (curType == hangulV || curType == hangulT):
+			join = true
+		case (prevType == hangulLVT || prevType == hangulT) && curType == hangulT:
+			join = true
+		case prev == zeroWidthJoiner && pictographic && isPictographic(r):
+			join = true
+		case riCount == 1 && isRegionalIndicator(r):
+			join = true
+			riCount = 2
+		}
+		if !join {
+			break
+		}
+		prev = r
+		n += size
+	}
+	return n
+}
+
+// countTextElements returns the number of text elements (grapheme clusters) in s.
+func countTextElements(s string) int {
+	count := 0
+	for len(s) > 0 {
+		s = s[textElementLen(s):]
+		count++
+	}
+	return count
+}
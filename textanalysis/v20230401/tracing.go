@@ -0,0 +1,32 @@
+package v20230401
+
+import (
+	"github.com/go-resty/resty/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+const tracerName = "github.com/kde713/azurelangai-go/textanalysis/v20230401"
+
+// Span attribute keys.
+const (
+	attrNamespace          = attribute.Key("az.namespace")
+	attrAPIVersion         = attribute.Key("az.language.api_version")
+	attrTaskKind           = attribute.Key("az.language.task_kind")
+	attrTaskCount          = attribute.Key("az.language.task_count")
+	attrDocumentCount      = attribute.Key("az.language.document_count")
+	attrDocumentErrorCount = attribute.Key("az.language.document_error_count")
+	attrJobID              = attribute.Key("az.language.job_id")
+	attrJobStatus          = attribute.Key("az.language.job_status")
+	attrRequestID          = attribute.Key("az.language.apim_request_id")
+	attrRetryCount         = attribute.Key("az.language.retry_count")
+	attrHTTPStatusCode     = attribute.Key("http.status_code")
+)
+
+// injectTraceContext propagates the trace context of the request to the service.
+func (c client) injectTraceContext(req *resty.Request) {
+	if c.tracer == nil {
+		return
+	}
+	c.propagator.Inject(req.Context(), propagation.HeaderCarrier(req.Header))
+}
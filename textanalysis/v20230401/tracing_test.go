@@ -0,0 +1,138 @@
+package v20230401_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/kde713/azurelangai-go/textanalysis/v20230401"
+)
+
+func spanAttributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func TestWithTracerProvider(t *testing.T) {
+	var requests int32
+	var traceparents []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparents = append(traceparents, r.Header.Get("traceparent"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("apim-request-id", "request-1")
+		switch {
+		case r.Method == http.MethodGet:
+			_, _ = w.Write([]byte(`{"jobId":"job-1","status":"succeeded","tasks":{"items":[{"kind":"KeyPhraseExtractionLROResults","status":"succeeded","results":{"documents":[],"errors":[{"id":"1","error":{"code":"InvalidArgument","message":"Document text is empty."}},{"id":"2","error":{"code":"InvalidArgument","message":"Document text is empty."}}],"modelVersion":"2022-10-01"}}]}}`))
+		case atomic.AddInt32(&requests, 1) == 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"error":{"code":"ServiceUnavailable","message":"Try again."}}`))
+		default:
+			_, _ = w.Write([]byte(`{"kind":"KeyPhraseExtractionResults","results":{"documents":[{"id":"1","keyPhrases":[],"warnings":[]}],"errors":[{"id":"2","error":{"code":"InvalidArgument","message":"Document text is empty."}}],"modelVersion":"2022-10-01"}}`))
+		}
+	}))
+	defer srv.Close()
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	client := v20230401.NewClient(srv.URL, "key",
+		v20230401.WithTracerProvider(provider),
+		v20230401.WithRetryCount(1, time.Millisecond, time.Millisecond),
+	)
+
+	_, err := client.AnalyzeTextKeyPhraseExtraction(context.TODO(), v20230401.MultiLanguageAnalysisInput{
+		Documents: []v20230401.MultiLanguageInput{{ID: "1", Text: "hello"}, {ID: "2", Text: ""}},
+	}, v20230401.KeyPhraseTaskParameters{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetTextAnalyticsJobResult(context.TODO(), "job-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("Expected 2 spans, got %d", len(spans))
+	}
+	analyzeSpan := spans[0]
+	if analyzeSpan.Name() != "Language.AnalyzeText" || analyzeSpan.SpanKind() != trace.SpanKindClient {
+		t.Errorf("Expected client span Language.AnalyzeText, got %s %s", analyzeSpan.SpanKind(), analyzeSpan.Name())
+	}
+	expected := map[attribute.Key]attribute.Value{
+		"az.language.task_kind":            attribute.StringValue(string(v20230401.TaskKindKeyPhraseExtraction)),
+		"az.language.api_version":          attribute.StringValue(v20230401.APIVersion),
+		"az.language.document_count":       attribute.IntValue(2),
+		"az.language.document_error_count": attribute.IntValue(1),
+		"az.language.apim_request_id":      attribute.StringValue("request-1"),
+		"az.language.retry_count":          attribute.IntValue(1),
+		"http.status_code":                 attribute.IntValue(http.StatusOK),
+	}
+	attrs := spanAttributes(analyzeSpan)
+	for key, value := range expected {
+		if attrs[key] != value {
+			t.Errorf("Expected %s=%s, got %s", key, value.Emit(), attrs[key].Emit())
+		}
+	}
+
+	jobAttrs := spanAttributes(spans[1])
+	if spans[1].Name() != "Language.GetJob" || jobAttrs["az.language.job_status"].AsString() != "succeeded" {
+		t.Errorf("Expected Language.GetJob span of a succeeded job, got %s %v", spans[1].Name(), jobAttrs)
+	}
+	if got := jobAttrs["az.language.document_error_count"].AsInt64(); got != 2 {
+		t.Errorf("Expected 2 document errors, got %d", got)
+	}
+
+	// Every attempt carries the trace context of its span.
+	if len(traceparents) != 3 {
+		t.Fatalf("Expected 3 requests, got %d", len(traceparents))
+	}
+	for i, traceparent := range traceparents {
+		span := spans[0]
+		if i == 2 {
+			span = spans[1]
+		}
+		if expected := "00-" + span.SpanContext().TraceID().String() + "-" + span.SpanContext().SpanID().String() + "-01"; traceparent != expected {
+			t.Errorf("Expected traceparent %s, got %s", expected, traceparent)
+		}
+	}
+}
+
+func TestWithTracerProvider_Error(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":{"code":"InvalidRequest","message":"Invalid Request."}}`))
+	}))
+	defer srv.Close()
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	client := v20230401.NewClient(srv.URL, "key", v20230401.WithTracerProvider(provider))
+
+	if _, err := client.SubmitTextAnalyticsJob(context.TODO(), newTestJobRequest(t, "hello")); err == nil {
+		t.Fatal("Expected error")
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("Expected 1 span, got %d", len(spans))
+	}
+	if spans[0].Name() != "Language.SubmitJob" || spans[0].Status().Code != codes.Error {
+		t.Errorf("Expected failed Language.SubmitJob span, got %s %v", spans[0].Name(), spans[0].Status())
+	}
+	if got := spanAttributes(spans[0])["http.status_code"].AsInt64(); got != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", got)
+	}
+	if len(spans[0].Events()) != 1 {
+		t.Errorf("Expected the error to be recorded, got %d events", len(spans[0].Events()))
+	}
+}